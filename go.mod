@@ -4,6 +4,7 @@ go 1.23.0
 
 require (
 	github.com/PNAP/go-sdk-helper-bmc v0.25.0
+	github.com/hashicorp/go-cty v1.4.1-0.20200414143053-d3edf31b6320
 	github.com/hashicorp/terraform-plugin-sdk/v2 v2.17.0
 	github.com/phoenixnap/go-sdk-bmc/billingapi/v4 v4.0.1
 	github.com/phoenixnap/go-sdk-bmc/bmcapi/v3 v3.5.0
@@ -13,6 +14,8 @@ require (
 	github.com/phoenixnap/go-sdk-bmc/networkstorageapi/v3 v3.0.5
 	github.com/phoenixnap/go-sdk-bmc/ranchersolutionapi/v3 v3.1.4
 	github.com/phoenixnap/go-sdk-bmc/tagapi/v3 v3.0.7
+	golang.org/x/oauth2 v0.27.0
+	gopkg.in/yaml.v3 v3.0.1
 //github.com/phoenixnap/pulumi-pnap/sdk v0.0.1-beta.3
 
 )
@@ -30,7 +33,6 @@ require (
 	github.com/hashicorp/errwrap v1.0.0 // indirect
 	github.com/hashicorp/go-checkpoint v0.5.0 // indirect
 	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
-	github.com/hashicorp/go-cty v1.4.1-0.20200414143053-d3edf31b6320 // indirect
 	github.com/hashicorp/go-hclog v1.2.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/hashicorp/go-plugin v1.4.4 // indirect
@@ -74,12 +76,10 @@ require (
 	go.uber.org/multierr v1.9.0 // indirect
 	golang.org/x/crypto v0.32.0 // indirect
 	golang.org/x/net v0.33.0 // indirect
-	golang.org/x/oauth2 v0.27.0 // indirect
 	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 	google.golang.org/appengine v1.6.6 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20241223144023-3abc09e42ca8 // indirect
 	google.golang.org/grpc v1.67.3 // indirect
 	google.golang.org/protobuf v1.36.1 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,15 @@
+package pnap
+
+import (
+	"strings"
+)
+
+// isNotFoundError checks whether the API responded to a request with 404 Not Found, which the helper SDK
+// reports in the error message together with the response code.
+func isNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	return strings.Contains(message, "Code: 404") || strings.Contains(message, "Code 404") || strings.Contains(message, "404 Not Found")
+}
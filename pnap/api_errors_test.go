@@ -0,0 +1,25 @@
+package pnap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsNotFoundError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{errors.New("API Returned Code: 404, Message: Server not found., Validation Errors: []"), true},
+		{errors.New("API Returned Code 404 Message: Resource not found. Validation Errors: []"), true},
+		{errors.New("404 Not Found"), true},
+		{errors.New("API Returned Code: 400, Message: Invalid server id 404., Validation Errors: []"), false},
+		{errors.New("API Returned Code: 500, Message: Internal error., Validation Errors: []"), false},
+	}
+	for _, c := range cases {
+		if actual := isNotFoundError(c.err); actual != c.expected {
+			t.Errorf("isNotFoundError(%v) = %t, expected %t", c.err, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/bgppeergroup"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	bgpAdvertisedRoutesDefault = "DEFAULT"
+	bgpAdvertisedRoutesNone    = "NONE"
+
+	bgpAsnVerified            = "VERIFIED"
+	bgpAsnPendingVerification = "PENDING_VERIFICATION"
+)
+
+// bgpAsnRange holds an inclusive range of ASNs.
+type bgpAsnRange struct {
+	first int64
+	last  int64
+}
+
+// bgpReservedAsns holds the ASNs that cannot be used for peering, as listed in the IANA registry.
+var bgpReservedAsns = []bgpAsnRange{
+	{0, 0},
+	{23456, 23456},
+	{64496, 64511},
+	{65535, 65551},
+	{4294967295, 4294967295},
+}
+
+// bgpPrivateAsns holds the ASN ranges reserved for private use by RFC 6996.
+var bgpPrivateAsns = []bgpAsnRange{
+	{64512, 65534},
+	{4200000000, 4294967294},
+}
+
+func asnInRanges(asn int64, ranges []bgpAsnRange) bool {
+	for _, r := range ranges {
+		if asn >= r.first && asn <= r.last {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBgpAsn checks that the ASN is a 32-bit ASN that is not reserved.
+func validateBgpAsn(v interface{}, k string) (warnings []string, errors []error) {
+	asn := int64(v.(int))
+	if asn < 0 || asn > 4294967295 {
+		errors = append(errors, fmt.Errorf("%s %d is not a valid ASN, expected 1 to 4294967294", k, asn))
+	} else if asnInRanges(asn, bgpReservedAsns) {
+		errors = append(errors, fmt.Errorf("%s %d is reserved and cannot be used for peering", k, asn))
+	}
+	return warnings, errors
+}
+
+// customizeDiffBgpPeerGroup checks during plan that a public ASN is either used for the first time in the account or
+// is verified or pending verification on the other BGP peer groups using it, and that the location has a bring your own IP Block before advertised routes are changed
+// from NONE to DEFAULT.
+func customizeDiffBgpPeerGroup(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client := m.(*providerMeta).client
+
+	if d.HasChange("asn") && d.NewValueKnown("asn") {
+		asn := int64(d.Get("asn").(int))
+		if !asnInRanges(asn, bgpPrivateAsns) {
+			requestCommand := bgppeergroup.NewGetBgpPeerGroupsCommand(client)
+			resp, err := requestCommand.Execute()
+			if err != nil {
+				return err
+			}
+			for _, j := range resp {
+				details := j.TargetAsnDetails
+				if j.Id == d.Id() || details.Asn != asn {
+					continue
+				}
+				if details.VerificationStatus == bgpAsnVerified || details.VerificationStatus == bgpAsnPendingVerification {
+					continue
+				}
+				reason := "no reason given"
+				if details.VerificationReason != nil {
+					reason = *details.VerificationReason
+				}
+				return fmt.Errorf("asn %d is %s on BGP peer group %s, expected %s or %s: %s", asn, details.VerificationStatus, j.Id, bgpAsnVerified, bgpAsnPendingVerification, reason)
+			}
+		}
+	}
+
+	if len(d.Id()) > 0 && d.HasChange("advertised_routes") && d.NewValueKnown("location") {
+		oldRoutes, newRoutes := d.GetChange("advertised_routes")
+		if oldRoutes.(string) == bgpAdvertisedRoutesNone && newRoutes.(string) == bgpAdvertisedRoutesDefault {
+			location := d.Get("location").(string)
+			requestCommand := ipblock.NewGetIpBlocksCommand(client)
+			resp, err := requestCommand.Execute()
+			if err != nil {
+				return err
+			}
+			for _, j := range resp {
+				if j.IsBringYourOwn != nil && *j.IsBringYourOwn && j.Location != nil && *j.Location == location {
+					return nil
+				}
+			}
+			return fmt.Errorf("advertised_routes can only be changed from %s to %s with a bring your own IP Block in location %s", bgpAdvertisedRoutesNone, bgpAdvertisedRoutesDefault, location)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package pnap
+
+import (
+	"testing"
+)
+
+func TestAsnInRanges(t *testing.T) {
+	cases := []struct {
+		asn      int64
+		ranges   []bgpAsnRange
+		expected bool
+	}{
+		{65401, bgpPrivateAsns, true},
+		{64512, bgpPrivateAsns, true},
+		{65534, bgpPrivateAsns, true},
+		{65535, bgpPrivateAsns, false},
+		{4200000000, bgpPrivateAsns, true},
+		{4294967294, bgpPrivateAsns, true},
+		{13335, bgpPrivateAsns, false},
+		{0, bgpReservedAsns, true},
+		{23456, bgpReservedAsns, true},
+		{64496, bgpReservedAsns, true},
+		{64511, bgpReservedAsns, true},
+		{65551, bgpReservedAsns, true},
+		{65552, bgpReservedAsns, false},
+		{4294967295, bgpReservedAsns, true},
+		{65401, bgpReservedAsns, false},
+		{65401, nil, false},
+	}
+	for _, c := range cases {
+		if actual := asnInRanges(c.asn, c.ranges); actual != c.expected {
+			t.Errorf("asnInRanges(%d, %v) = %t, expected %t", c.asn, c.ranges, actual, c.expected)
+		}
+	}
+}
+
+func TestValidateBgpAsn(t *testing.T) {
+	cases := []struct {
+		asn     int
+		invalid bool
+	}{
+		{1, false},
+		{13335, false},
+		{65401, false},
+		{4200000000, false},
+		{4294967294, false},
+		{0, true},
+		{-1, true},
+		{23456, true},
+		{64500, true},
+		{65535, true},
+		{4294967295, true},
+		{4294967296, true},
+	}
+	for _, c := range cases {
+		warnings, errors := validateBgpAsn(c.asn, "asn")
+		if len(warnings) > 0 {
+			t.Errorf("validateBgpAsn(%d) returned warnings %v", c.asn, warnings)
+		}
+		if invalid := len(errors) > 0; invalid != c.invalid {
+			t.Errorf("validateBgpAsn(%d) returned errors %v, expected invalid %t", c.asn, errors, c.invalid)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package pnap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	cloudInitMultipartBoundary = "PNAP-CLOUD-INIT-BOUNDARY"
+	cloudInitContentTypeConfig = "text/cloud-config"
+)
+
+// cloudInitPartContentTypes lists the content types of multipart user data parts supported by cloud-init.
+var cloudInitPartContentTypes = []string{
+	cloudInitContentTypeConfig,
+	"text/x-shellscript",
+	"text/cloud-boothook",
+	"text/x-include-url",
+	"text/part-handler",
+	"text/jinja2",
+}
+
+// cloudInitHeaders maps the first line prefixes of user data understood by cloud-init to their content types.
+var cloudInitHeaders = map[string]string{
+	"#cloud-config":           cloudInitContentTypeConfig,
+	"#!":                      "text/x-shellscript",
+	"#cloud-boothook":         "text/cloud-boothook",
+	"#include":                "text/x-include-url",
+	"#part-handler":           "text/part-handler",
+	"## template: jinja":      "text/jinja2",
+	"Content-Type: multipart": "multipart/mixed",
+}
+
+// buildCloudInitUserData returns the base64 encoded user data of a cloud_init block. User data is read from
+// user_data as base64, from content as raw text or assembled from part blocks as multipart MIME, and optionally
+// compressed with gzip. Raw text is validated, user_data is passed on as is, as before content and part existed.
+func buildCloudInitUserData(cloudInitItem map[string]interface{}) (string, error) {
+	var data []byte
+	var raw bool
+	userData, _ := cloudInitItem["user_data"].(string)
+	content, _ := cloudInitItem["content"].(string)
+	parts, _ := cloudInitItem["part"].([]interface{})
+	switch {
+	case len(userData) > 0:
+		decoded, err := base64.StdEncoding.DecodeString(userData)
+		if err != nil {
+			return "", fmt.Errorf("cloud_init user_data is not valid base64: %v", err)
+		}
+		data = decoded
+	case len(content) > 0:
+		data = []byte(content)
+		raw = true
+	case len(parts) > 0:
+		multipartData, err := buildCloudInitMultipart(parts)
+		if err != nil {
+			return "", err
+		}
+		data = multipartData
+	default:
+		return "", nil
+	}
+
+	// Compressed user data is passed on as is.
+	if !isGzip(data) {
+		if raw {
+			if err := validateCloudInitUserData(data); err != nil {
+				return "", err
+			}
+		}
+		if compress, _ := cloudInitItem["gzip"].(bool); compress {
+			var buf bytes.Buffer
+			writer := gzip.NewWriter(&buf)
+			if _, err := writer.Write(data); err != nil {
+				return "", err
+			}
+			if err := writer.Close(); err != nil {
+				return "", err
+			}
+			data = buf.Bytes()
+		}
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// flattenCloudInit returns the cloud_init block for the user data read from the API. The configured block,
+// whether it uses user_data, content or part, is kept as long as it still builds the same user data, so that
+// only user data changed outside of Terraform is reported as a difference.
+func flattenCloudInit(userDataApi string, cloudInitInput []interface{}) []interface{} {
+	if len(cloudInitInput) > 0 {
+		if cloudInitItem, ok := cloudInitInput[0].(map[string]interface{}); ok {
+			if userData, err := buildCloudInitUserData(cloudInitItem); err == nil && userData == userDataApi {
+				return cloudInitInput
+			}
+		}
+	}
+	cloudInit := make([]interface{}, 1)
+	cloudInitItem := make(map[string]interface{})
+	cloudInitItem["user_data"] = userDataApi
+	cloudInit[0] = cloudInitItem
+	return cloudInit
+}
+
+// buildCloudInitMultipart assembles part blocks into a multipart MIME document.
+func buildCloudInitMultipart(parts []interface{}) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.SetBoundary(cloudInitMultipartBoundary); err != nil {
+		return nil, err
+	}
+	for i, v := range parts {
+		partItem := v.(map[string]interface{})
+		contentType := partItem["content_type"].(string)
+		content := partItem["content"].(string)
+		if contentType == cloudInitContentTypeConfig {
+			if err := validateCloudConfig([]byte(content)); err != nil {
+				return nil, fmt.Errorf("cloud_init part %d: %v", i, err)
+			}
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", contentType+"; charset=\"utf-8\"")
+		header.Set("MIME-Version", "1.0")
+		header.Set("Content-Transfer-Encoding", "7bit")
+		if filename := partItem["filename"].(string); len(filename) > 0 {
+			header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		}
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := partWriter.Write([]byte(content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var data bytes.Buffer
+	fmt.Fprintf(&data, "Content-Type: multipart/mixed; boundary=%q\r\nMIME-Version: 1.0\r\n\r\n", cloudInitMultipartBoundary)
+	data.Write(body.Bytes())
+	return data.Bytes(), nil
+}
+
+// validateCloudInitUserData checks that user data starts with a header understood by cloud-init,
+// and that cloud-config user data is a YAML mapping.
+func validateCloudInitUserData(data []byte) error {
+	for header, contentType := range cloudInitHeaders {
+		if !bytes.HasPrefix(data, []byte(header)) {
+			continue
+		}
+		if contentType == cloudInitContentTypeConfig {
+			return validateCloudConfig(data)
+		}
+		return nil
+	}
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+	return fmt.Errorf("cloud_init user data starts with %q, expected a header such as #cloud-config or #!", firstLine)
+}
+
+// validateCloudConfig checks that cloud-config data is a YAML mapping.
+func validateCloudConfig(data []byte) error {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("invalid cloud-config YAML: %v", err)
+	}
+	return nil
+}
+
+func isGzip(data []byte) bool {
+	return len(data) > 1 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// customizeDiffServerCloudInit validates the cloud-init user data during plan.
+func customizeDiffServerCloudInit(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("cloud_init") || !d.GetRawConfig().GetAttr("cloud_init").IsWhollyKnown() {
+		return nil
+	}
+	cloudInit := d.Get("cloud_init").([]interface{})
+	if len(cloudInit) == 0 || cloudInit[0] == nil {
+		return nil
+	}
+	_, err := buildCloudInitUserData(cloudInit[0].(map[string]interface{}))
+	return err
+}
@@ -0,0 +1,99 @@
+package pnap
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestFlattenCloudInit_readRoundTrip(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"user_data": {
+			"user_data": base64.StdEncoding.EncodeToString([]byte("#cloud-config\nhostname: server-1\n")),
+		},
+		"content": {
+			"content": "#cloud-config\npackages:\n  - nginx\n",
+		},
+		"content gzip": {
+			"content": "#!/bin/bash\necho hello\n",
+			"gzip":    true,
+		},
+		"part": {
+			"part": []interface{}{
+				map[string]interface{}{
+					"content_type": cloudInitContentTypeConfig,
+					"content":      "#cloud-config\npackages:\n  - nginx\n",
+				},
+				map[string]interface{}{
+					"content_type": "text/x-shellscript",
+					"filename":     "setup.sh",
+					"content":      "#!/bin/bash\necho hello\n",
+				},
+			},
+		},
+	}
+	for name, cloudInitItem := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceServer().Schema, map[string]interface{}{
+				"cloud_init": []interface{}{cloudInitItem},
+			})
+			before := d.Get("cloud_init").([]interface{})
+			userDataApi, err := buildCloudInitUserData(before[0].(map[string]interface{}))
+			if err != nil {
+				t.Fatalf("building user data: %v", err)
+			}
+
+			// Read sets cloud_init from the user data returned by the API.
+			if err := d.Set("cloud_init", flattenCloudInit(userDataApi, d.Get("cloud_init").([]interface{}))); err != nil {
+				t.Fatalf("setting cloud_init: %v", err)
+			}
+			if after := d.Get("cloud_init").([]interface{}); !reflect.DeepEqual(before, after) {
+				t.Errorf("cloud_init changed on read:\nbefore: %#v\nafter:  %#v", before, after)
+			}
+		})
+	}
+}
+
+func TestFlattenCloudInit_changedOutside(t *testing.T) {
+	userDataApi := base64.StdEncoding.EncodeToString([]byte("#cloud-config\nhostname: changed\n"))
+	cloudInitInput := []interface{}{
+		map[string]interface{}{
+			"content": "#cloud-config\nhostname: server-1\n",
+		},
+	}
+	cloudInit := flattenCloudInit(userDataApi, cloudInitInput)
+	if len(cloudInit) != 1 || cloudInit[0].(map[string]interface{})["user_data"] != userDataApi {
+		t.Errorf("expected user_data %s read from the API, got %#v", userDataApi, cloudInit)
+	}
+
+	cloudInit = flattenCloudInit(userDataApi, nil)
+	if len(cloudInit) != 1 || cloudInit[0].(map[string]interface{})["user_data"] != userDataApi {
+		t.Errorf("expected user_data %s for an imported server, got %#v", userDataApi, cloudInit)
+	}
+}
+
+func TestBuildCloudInitUserData_validation(t *testing.T) {
+	cases := []struct {
+		name          string
+		cloudInitItem map[string]interface{}
+		valid         bool
+	}{
+		{"user_data without header", map[string]interface{}{"user_data": base64.StdEncoding.EncodeToString([]byte("hostname: server-1\n"))}, true},
+		{"user_data with invalid cloud-config", map[string]interface{}{"user_data": base64.StdEncoding.EncodeToString([]byte("#cloud-config\n: [\n"))}, true},
+		{"user_data not base64", map[string]interface{}{"user_data": "#cloud-config"}, false},
+		{"content without header", map[string]interface{}{"content": "hostname: server-1\n"}, false},
+		{"content with invalid cloud-config", map[string]interface{}{"content": "#cloud-config\n: [\n"}, false},
+		{"content script", map[string]interface{}{"content": "#!/bin/bash\necho hello\n"}, true},
+		{"part with invalid cloud-config", map[string]interface{}{"part": []interface{}{
+			map[string]interface{}{"content_type": cloudInitContentTypeConfig, "content": "#cloud-config\n: [\n", "filename": ""},
+		}}, false},
+	}
+	for _, c := range cases {
+		_, err := buildCloudInitUserData(c.cloudInitItem)
+		if valid := err == nil; valid != c.valid {
+			t.Errorf("%s: buildCloudInitUserData() returned error %v, expected valid %t", c.name, err, c.valid)
+		}
+	}
+}
@@ -1,12 +1,12 @@
 package pnap
 
 import (
-	"fmt"
+	"time"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/bgppeergroup"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
 
 func dataSourceBgpPeerGroup() *schema.Resource {
@@ -31,6 +31,11 @@ func dataSourceBgpPeerGroup() *schema.Resource {
 				Computed:      true,
 				ConflictsWith: []string{"id"},
 			},
+			"most_recent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"ipv4_prefixes": { // Deprecated
 				Type:     schema.TypeList,
 				Computed: true,
@@ -179,133 +184,83 @@ func dataSourceBgpPeerGroup() *schema.Resource {
 }
 
 func dataSourceBgpPeerGroupRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
-	bgpID := d.Get("id").(string)
-	if len(bgpID) > 0 {
+	filter, err := newLookupFilter(d, "BGP peer group", "")
+	if err != nil {
+		return err
+	}
+	var resp []networkapiclient.BgpPeerGroup
+	if len(d.Get("id").(string)) > 0 {
 		requestCommand := bgppeergroup.NewGetBgpPeerGroupsCommand(client)
-		resp, err := requestCommand.Execute()
-		if err != nil {
-			return err
-		}
-		numOfGroups := 0
-		for _, instance := range resp {
-			if instance.Id == bgpID {
-				numOfGroups++
-				d.SetId(instance.Id)
-				d.Set("status", instance.Status)
-				d.Set("location", instance.Location)
-
-				ipv4Prefixes := flattenIpv4Prefixes(instance.Ipv4Prefixes)
-				if err := d.Set("ipv4_prefixes", ipv4Prefixes); err != nil {
-					return err
-				}
-				ipPrefixes := flattenIpPrefixes(instance.IpPrefixes)
-				if err := d.Set("ip_prefixes", ipPrefixes); err != nil {
-					return err
-				}
-				target := instance.TargetAsnDetails
-				targetAsnDetails := flattenAsnDetails(&target)
-				if err := d.Set("target_asn_details", targetAsnDetails); err != nil {
-					return err
-				}
-				activeAsnDetails := flattenAsnDetails(instance.ActiveAsnDetails)
-				if err := d.Set("active_asn_details", activeAsnDetails); err != nil {
-					return err
-				}
-				d.Set("password", instance.Password)
-				d.Set("advertised_routes", instance.AdvertisedRoutes)
-				d.Set("rpki_roa_origin_asn", int(instance.RpkiRoaOriginAsn))
-				d.Set("ebgp_multi_hop", int(instance.EBgpMultiHop))
-				var peeringLoopbacks []interface{}
-				for _, v := range instance.PeeringLoopbacksV4 {
-					peeringLoopbacks = append(peeringLoopbacks, v)
-				}
-				d.Set("peering_loopbacks_v4", peeringLoopbacks)
-				var peeringLoopbacks6 []interface{}
-				for _, v6 := range instance.PeeringLoopbacksV6 {
-					peeringLoopbacks6 = append(peeringLoopbacks6, v6)
-				}
-				d.Set("peering_loopbacks_v6", peeringLoopbacks6)
-				d.Set("keep_alive_timer_seconds", int(instance.KeepAliveTimerSeconds))
-				d.Set("hold_timer_seconds", int(instance.HoldTimerSeconds))
-
-				if instance.CreatedOn != nil {
-					createdOn := *instance.CreatedOn
-					d.Set("created_on", createdOn)
-				}
-				if instance.LastUpdatedOn != nil {
-					lastUpdatedOn := *instance.LastUpdatedOn
-					d.Set("last_updated_on", lastUpdatedOn)
-				}
-			}
-		}
-		if numOfGroups > 1 {
-			return fmt.Errorf("too many BGP Peer Groups with id %s (found %d, expected 1)", d.Get("id").(string), numOfGroups)
-		}
-		return nil
+		resp, err = requestCommand.Execute()
 	} else {
 		query := dto.Query{}
 		location := d.Get("location").(string)
 		query.LocationString = location
+		filter.addCriteria("location", location)
 		requestCommand := bgppeergroup.NewGetBgpPeerGroupsWithQueryCommand(client, &query)
-		resp, err := requestCommand.Execute()
-		if err != nil {
-			return err
+		resp, err = requestCommand.Execute()
+	}
+	if err != nil {
+		return err
+	}
+	instance, err := lookupOne(filter, resp, func(instance networkapiclient.BgpPeerGroup) lookupItem {
+		item := lookupItem{id: instance.Id}
+		if instance.CreatedOn != nil {
+			item.createdOn, _ = time.Parse(time.RFC3339, *instance.CreatedOn)
 		}
-		numOfGroups := 0
-		for _, instance := range resp {
-			numOfGroups++
-			d.SetId(instance.Id)
-			d.Set("status", instance.Status)
-			d.Set("location", instance.Location)
+		return item
+	})
+	if err != nil {
+		return err
+	}
 
-			ipv4Prefixes := flattenIpv4Prefixes(instance.Ipv4Prefixes)
-			if err := d.Set("ipv4_prefixes", ipv4Prefixes); err != nil {
-				return err
-			}
-			ipPrefixes := flattenIpPrefixes(instance.IpPrefixes)
-			if err := d.Set("ip_prefixes", ipPrefixes); err != nil {
-				return err
-			}
-			target := instance.TargetAsnDetails
-			targetAsnDetails := flattenAsnDetails(&target)
-			if err := d.Set("target_asn_details", targetAsnDetails); err != nil {
-				return err
-			}
-			activeAsnDetails := flattenAsnDetails(instance.ActiveAsnDetails)
-			if err := d.Set("active_asn_details", activeAsnDetails); err != nil {
-				return err
-			}
-			d.Set("password", instance.Password)
-			d.Set("advertised_routes", instance.AdvertisedRoutes)
-			d.Set("rpki_roa_origin_asn", int(instance.RpkiRoaOriginAsn))
-			d.Set("ebgp_multi_hop", int(instance.EBgpMultiHop))
-			var peeringLoopbacks []interface{}
-			for _, v := range instance.PeeringLoopbacksV4 {
-				peeringLoopbacks = append(peeringLoopbacks, v)
-			}
-			d.Set("peering_loopbacks_v4", peeringLoopbacks)
-			var peeringLoopbacks6 []interface{}
-			for _, v6 := range instance.PeeringLoopbacksV6 {
-				peeringLoopbacks6 = append(peeringLoopbacks6, v6)
-			}
-			d.Set("peering_loopbacks_v6", peeringLoopbacks6)
-			d.Set("keep_alive_timer_seconds", int(instance.KeepAliveTimerSeconds))
-			d.Set("hold_timer_seconds", int(instance.HoldTimerSeconds))
+	d.SetId(instance.Id)
+	d.Set("status", instance.Status)
+	d.Set("location", instance.Location)
 
-			if instance.CreatedOn != nil {
-				createdOn := *instance.CreatedOn
-				d.Set("created_on", createdOn)
-			}
-			if instance.LastUpdatedOn != nil {
-				lastUpdatedOn := *instance.LastUpdatedOn
-				d.Set("last_updated_on", lastUpdatedOn)
-			}
-		}
-		if numOfGroups > 1 {
-			return fmt.Errorf("too many BGP Peer Groups with location %s (found %d, expected 1)", d.Get("location").(string), numOfGroups)
-		}
-		return nil
+	ipv4Prefixes := flattenIpv4Prefixes(instance.Ipv4Prefixes)
+	if err := d.Set("ipv4_prefixes", ipv4Prefixes); err != nil {
+		return err
+	}
+	ipPrefixes := flattenIpPrefixes(instance.IpPrefixes)
+	if err := d.Set("ip_prefixes", ipPrefixes); err != nil {
+		return err
+	}
+	target := instance.TargetAsnDetails
+	targetAsnDetails := flattenAsnDetails(&target)
+	if err := d.Set("target_asn_details", targetAsnDetails); err != nil {
+		return err
+	}
+	activeAsnDetails := flattenAsnDetails(instance.ActiveAsnDetails)
+	if err := d.Set("active_asn_details", activeAsnDetails); err != nil {
+		return err
+	}
+	d.Set("password", instance.Password)
+	d.Set("advertised_routes", instance.AdvertisedRoutes)
+	d.Set("rpki_roa_origin_asn", int(instance.RpkiRoaOriginAsn))
+	d.Set("ebgp_multi_hop", int(instance.EBgpMultiHop))
+	var peeringLoopbacks []interface{}
+	for _, v := range instance.PeeringLoopbacksV4 {
+		peeringLoopbacks = append(peeringLoopbacks, v)
+	}
+	d.Set("peering_loopbacks_v4", peeringLoopbacks)
+	var peeringLoopbacks6 []interface{}
+	for _, v6 := range instance.PeeringLoopbacksV6 {
+		peeringLoopbacks6 = append(peeringLoopbacks6, v6)
+	}
+	d.Set("peering_loopbacks_v6", peeringLoopbacks6)
+	d.Set("keep_alive_timer_seconds", int(instance.KeepAliveTimerSeconds))
+	d.Set("hold_timer_seconds", int(instance.HoldTimerSeconds))
+
+	if instance.CreatedOn != nil {
+		createdOn := *instance.CreatedOn
+		d.Set("created_on", createdOn)
+	}
+	if instance.LastUpdatedOn != nil {
+		lastUpdatedOn := *instance.LastUpdatedOn
+		d.Set("last_updated_on", lastUpdatedOn)
 	}
+	return nil
 }
@@ -0,0 +1,163 @@
+package pnap
+
+import (
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/bgppeergroup"
+	"github.com/PNAP/go-sdk-helper-bmc/dto"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const bgpStatusReady = "READY"
+
+// dataSourceBgpSessions reports the provisioning state of the BGP peer groups in a location. The Network API
+// exposes no live session state, such as established or idle sessions and received prefixes, so a peer group
+// counts as ready when it and all its prefixes are provisioned.
+func dataSourceBgpSessions() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBgpSessionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ready": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"bgp_peer_groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"asn": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"asn_verification_status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"neighbor_ips_v4": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"neighbor_ips_v6": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"advertised_routes": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"prefixes": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"cidr": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"ip_version": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"status": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"ready_prefixes_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"ready": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceBgpSessionsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	location := d.Get("location").(string)
+
+	query := dto.Query{}
+	query.LocationString = location
+	requestCommand := bgppeergroup.NewGetBgpPeerGroupsWithQueryCommand(client, &query)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+
+	allReady := len(resp) > 0
+	peerGroups := make([]interface{}, len(resp))
+	for i, j := range resp {
+		peerGroup := make(map[string]interface{})
+		peerGroup["id"] = j.Id
+		peerGroup["status"] = j.Status
+
+		asnDetails := j.TargetAsnDetails
+		if j.ActiveAsnDetails != nil {
+			asnDetails = *j.ActiveAsnDetails
+		}
+		peerGroup["asn"] = int(asnDetails.Asn)
+		peerGroup["asn_verification_status"] = asnDetails.VerificationStatus
+
+		neighborsV4 := make([]interface{}, len(j.PeeringLoopbacksV4))
+		for k, v := range j.PeeringLoopbacksV4 {
+			neighborsV4[k] = v
+		}
+		peerGroup["neighbor_ips_v4"] = neighborsV4
+		neighborsV6 := make([]interface{}, len(j.PeeringLoopbacksV6))
+		for k, v := range j.PeeringLoopbacksV6 {
+			neighborsV6[k] = v
+		}
+		peerGroup["neighbor_ips_v6"] = neighborsV6
+		peerGroup["advertised_routes"] = j.AdvertisedRoutes
+
+		ready := j.Status == bgpStatusReady
+		readyPrefixes := 0
+		prefixes := make([]interface{}, len(j.IpPrefixes))
+		for k, v := range j.IpPrefixes {
+			prefix := make(map[string]interface{})
+			prefix["cidr"] = v.Cidr
+			prefix["ip_version"] = v.IpVersion
+			prefix["status"] = v.Status
+			prefixes[k] = prefix
+			if v.Status == bgpStatusReady {
+				readyPrefixes++
+			} else {
+				ready = false
+			}
+		}
+		peerGroup["prefixes"] = prefixes
+		peerGroup["ready_prefixes_count"] = readyPrefixes
+		peerGroup["ready"] = ready
+		allReady = allReady && ready
+		peerGroups[i] = peerGroup
+	}
+
+	d.SetId(location)
+	d.Set("ready", allReady)
+	if err := d.Set("bgp_peer_groups", peerGroups); err != nil {
+		return err
+	}
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/auditapi/event"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -87,7 +86,7 @@ func dataSourceEvents() *schema.Resource {
 }
 
 func dataSourceEventsRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	query := dto.Query{}
 
 	from := d.Get("from").(string)
@@ -10,7 +10,6 @@ import (
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/invoicingapi/invoice"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -124,7 +123,7 @@ func dataSourceInvoices() *schema.Resource {
 }
 
 func dataSourceInvoicesRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	query := dto.Query{}
 	query.Number = d.Get("number").(string)
 	query.Status = d.Get("status").(string)
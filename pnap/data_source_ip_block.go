@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/phoenixnap/go-sdk-bmc/ipapi/v3"
 )
@@ -103,7 +102,7 @@ func dataSourceIpBlock() *schema.Resource {
 }
 
 func dataSourceIpBlockRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	requestCommand := ipblock.NewGetIpBlocksCommand(client)
 	resp, err := requestCommand.Execute()
 	if err != nil {
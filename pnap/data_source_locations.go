@@ -6,7 +6,6 @@ import (
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/locationapi/location"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	locationapiclient "github.com/phoenixnap/go-sdk-bmc/locationapi/v4"
 )
@@ -61,7 +60,7 @@ func dataSourceLocations() *schema.Resource {
 }
 
 func dataSourceLocationsRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	query := dto.Query{}
 
 	loc := d.Get("location").(string)
@@ -1,12 +1,11 @@
 package pnap
 
 import (
-	"fmt"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/privatenetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 )
 
 func dataSourcePrivateNetwork() *schema.Resource {
@@ -27,6 +26,17 @@ func dataSourcePrivateNetwork() *schema.Resource {
 				Computed:      true,
 				ConflictsWith: []string{"id"},
 			},
+			"name_regex": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringIsValidRegExp,
+				ConflictsWith: []string{"id", "name"},
+			},
+			"most_recent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"location": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -102,44 +112,46 @@ func dataSourcePrivateNetwork() *schema.Resource {
 }
 
 func dataSourcePrivateNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	requestCommand := privatenetwork.NewGetPrivateNetworksCommand(client)
 	resp, err := requestCommand.Execute()
 	if err != nil {
 		return err
 	}
 
-	numOfNets := 0
-	for _, instance := range resp {
-		if instance.Name == d.Get("name").(string) || instance.Id == d.Get("id").(string) {
-			numOfNets++
-			d.SetId(instance.Id)
-			d.Set("location", instance.Location)
-			d.Set("name", instance.Name)
-			d.Set("cidr", instance.Cidr)
-			d.Set("description", instance.Description)
-			d.Set("location_default", instance.LocationDefault)
-			d.Set("type", instance.Type)
-			d.Set("vlan_id", instance.VlanId)
-			servers := flattenServers(instance.Servers)
+	filter, err := newLookupFilter(d, "private network", "name")
+	if err != nil {
+		return err
+	}
+	instance, err := lookupOne(filter, resp, func(instance networkapiclient.PrivateNetwork) lookupItem {
+		return lookupItem{id: instance.Id, name: instance.Name, createdOn: instance.CreatedOn}
+	})
+	if err != nil {
+		return err
+	}
 
-			if err := d.Set("servers", servers); err != nil {
-				return err
-			}
-			memberships := flattenMemberships(instance.Memberships)
+	d.SetId(instance.Id)
+	d.Set("location", instance.Location)
+	d.Set("name", instance.Name)
+	d.Set("cidr", instance.Cidr)
+	d.Set("description", instance.Description)
+	d.Set("location_default", instance.LocationDefault)
+	d.Set("type", instance.Type)
+	d.Set("vlan_id", instance.VlanId)
+	servers := flattenServers(instance.Servers)
 
-			if err := d.Set("memberships", memberships); err != nil {
-				return err
-			}
-			d.Set("status", instance.Status)
+	if err := d.Set("servers", servers); err != nil {
+		return err
+	}
+	memberships := flattenMemberships(instance.Memberships)
 
-			if len(instance.CreatedOn.String()) > 0 {
-				d.Set("created_on", instance.CreatedOn.String())
-			}
-		}
+	if err := d.Set("memberships", memberships); err != nil {
+		return err
 	}
-	if numOfNets > 1 {
-		return fmt.Errorf("too many private networks with name %s (found %d, expected 1)", d.Get("name").(string), numOfNets)
+	d.Set("status", instance.Status)
+
+	if len(instance.CreatedOn.String()) > 0 {
+		d.Set("created_on", instance.CreatedOn.String())
 	}
 	return nil
 }
@@ -9,7 +9,6 @@ import (
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/product"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -98,7 +97,7 @@ func dataSourceProductAvailability() *schema.Resource {
 }
 
 func dataSourceProductAvailabilityRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	query := dto.ProductAvailabilityQuery{}
 	proCatTemp := d.Get("product_category").(*schema.Set).List()
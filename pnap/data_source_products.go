@@ -7,7 +7,6 @@ import (
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/product"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -213,7 +212,7 @@ func dataSourceProducts() *schema.Resource {
 }
 
 func dataSourceProductsRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	query := dto.ProductQuery{}
 	query.ProductCode = d.Get("product_code").(string)
 	query.ProductCategory = d.Get("product_category").(string)
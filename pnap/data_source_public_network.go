@@ -1,13 +1,11 @@
 package pnap
 
 import (
-	"fmt"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 )
 
 func dataSourcePublicNetwork() *schema.Resource {
@@ -28,6 +26,17 @@ func dataSourcePublicNetwork() *schema.Resource {
 				Computed:      true,
 				ConflictsWith: []string{"id"},
 			},
+			"name_regex": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringIsValidRegExp,
+				ConflictsWith: []string{"id", "name"},
+			},
+			"most_recent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"location": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -98,50 +107,48 @@ func dataSourcePublicNetwork() *schema.Resource {
 }
 
 func dataSourcePublicNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	requestCommand := publicnetwork.NewGetPublicNetworksCommand(client)
 	resp, err := requestCommand.Execute()
 	if err != nil {
 		return err
 	}
 
-	numOfNets := 0
-	name := d.Get("name").(string)
-	id := d.Get("id").(string)
-	for _, instance := range resp {
-		if instance.Name == name || instance.Id == id {
-			numOfNets++
-			d.SetId(instance.Id)
-			d.Set("location", instance.Location)
-			d.Set("name", instance.Name)
-			if instance.Description != nil {
-				d.Set("description", *instance.Description)
-			} else {
-				d.Set("description", "")
-			}
-			ipBlocks := flattenDataIpBlocks(instance.IpBlocks)
-			if err := d.Set("ip_blocks", ipBlocks); err != nil {
-				return err
-			}
-			d.Set("created_on", instance.CreatedOn.String())
-			d.Set("vlan_id", instance.VlanId)
+	filter, err := newLookupFilter(d, "public network", "name")
+	if err != nil {
+		return err
+	}
+	instance, err := lookupOne(filter, resp, func(instance networkapiclient.PublicNetwork) lookupItem {
+		return lookupItem{id: instance.Id, name: instance.Name, createdOn: instance.CreatedOn}
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(instance.Id)
+	d.Set("location", instance.Location)
+	d.Set("name", instance.Name)
+	if instance.Description != nil {
+		d.Set("description", *instance.Description)
+	} else {
+		d.Set("description", "")
+	}
+	ipBlocks := flattenDataIpBlocks(instance.IpBlocks)
+	if err := d.Set("ip_blocks", ipBlocks); err != nil {
+		return err
+	}
+	d.Set("created_on", instance.CreatedOn.String())
+	d.Set("vlan_id", instance.VlanId)
 
-			memberships := flattenMemberships(instance.Memberships)
-			if err := d.Set("memberships", memberships); err != nil {
-				return err
-			}
-			d.Set("status", instance.Status)
-			if instance.RaEnabled != nil {
-				d.Set("ra_enabled", *instance.RaEnabled)
-			} else {
-				d.Set("ra_enabled", nil)
-			}
-		}
+	memberships := flattenMemberships(instance.Memberships)
+	if err := d.Set("memberships", memberships); err != nil {
+		return err
 	}
-	if numOfNets > 1 && len(name) > 0 {
-		return fmt.Errorf("too many public networks with name %s (found %d, expected 1)", name, numOfNets)
-	} else if numOfNets > 1 && len(id) > 0 {
-		return fmt.Errorf("too many public networks with ID %s (found %d, expected 1)", id, numOfNets)
+	d.Set("status", instance.Status)
+	if instance.RaEnabled != nil {
+		d.Set("ra_enabled", *instance.RaEnabled)
+	} else {
+		d.Set("ra_enabled", nil)
 	}
 
 	return nil
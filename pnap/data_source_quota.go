@@ -1,11 +1,10 @@
 package pnap
 
 import (
-	"fmt"
-
 	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/quota"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
 )
 
 func dataSourceQuota() *schema.Resource {
@@ -25,6 +24,12 @@ func dataSourceQuota() *schema.Resource {
 				Computed:      true,
 				ConflictsWith: []string{"id"},
 			},
+			"name_regex": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringIsValidRegExp,
+				ConflictsWith: []string{"id", "name"},
+			},
 			"description": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -70,38 +75,40 @@ func dataSourceQuota() *schema.Resource {
 }
 
 func dataSourceQuotaRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	requestCommand := quota.NewGetQuotasCommand(client)
 	resp, err := requestCommand.Execute()
 	if err != nil {
 		return err
 	}
-	numOfQuotas := 0
-	for _, instance := range resp {
-		if instance.Name == d.Get("name").(string) || instance.Id == d.Get("id").(string) {
-			numOfQuotas++
-			d.SetId(instance.Id)
-			d.Set("name", instance.Name)
-			d.Set("description", instance.Description)
-			d.Set("status", instance.Status)
-			d.Set("limit", int(instance.Limit))
-			d.Set("unit", instance.Unit)
-			d.Set("used", int(instance.Used))
-			quotaRequests := instance.QuotaEditLimitRequestDetails
-			qelrd := make([]interface{}, len(quotaRequests))
-			for i, j := range quotaRequests {
-				qelrdItem := make(map[string]interface{})
-				qelrdItem["limit"] = int(j.Limit)
-				qelrdItem["reason"] = j.Reason
-				qelrdItem["requested_on"] = j.RequestedOn.String()
-				qelrd[i] = qelrdItem
-			}
-			d.Set("quota_edit_limit_request_details", qelrd)
-		}
+	filter, err := newLookupFilter(d, "quota", "name")
+	if err != nil {
+		return err
+	}
+	instance, err := lookupOne(filter, resp, func(instance bmcapiclient.Quota) lookupItem {
+		return lookupItem{id: instance.Id, name: instance.Name}
+	})
+	if err != nil {
+		return err
 	}
-	if numOfQuotas > 1 {
-		return fmt.Errorf("too many Quotas with name %s (found %d, expected 1)", d.Get("name").(string), numOfQuotas)
+
+	d.SetId(instance.Id)
+	d.Set("name", instance.Name)
+	d.Set("description", instance.Description)
+	d.Set("status", instance.Status)
+	d.Set("limit", int(instance.Limit))
+	d.Set("unit", instance.Unit)
+	d.Set("used", int(instance.Used))
+	quotaRequests := instance.QuotaEditLimitRequestDetails
+	qelrd := make([]interface{}, len(quotaRequests))
+	for i, j := range quotaRequests {
+		qelrdItem := make(map[string]interface{})
+		qelrdItem["limit"] = int(j.Limit)
+		qelrdItem["reason"] = j.Reason
+		qelrdItem["requested_on"] = j.RequestedOn.String()
+		qelrd[i] = qelrdItem
 	}
+	d.Set("quota_edit_limit_request_details", qelrd)
 
 	return nil
 }
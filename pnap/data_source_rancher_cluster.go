@@ -6,7 +6,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/ranchersolutionapi/cluster"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 )
 
 func dataSourceRancherCluster() *schema.Resource {
@@ -90,7 +89,7 @@ func dataSourceRancherCluster() *schema.Resource {
 
 func dataSourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
 	if len(d.Get("name").(string)) > 0 {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 
 		requestCommand := cluster.NewGetClustersCommand(client)
 		resp, err := requestCommand.Execute()
@@ -187,7 +186,7 @@ func dataSourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
 		}
 
 	} else if len(d.Get("id").(string)) > 0 {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		clusterID := d.Get("id").(string)
 		requestCommand := cluster.NewGetClusterCommand(client, clusterID)
 		resp, err := requestCommand.Execute()
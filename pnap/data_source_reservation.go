@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/reservation"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -148,7 +147,7 @@ func dataSourceReservation() *schema.Resource {
 }
 
 func dataSourceReservationRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	requestCommand := reservation.NewGetReservationsCommand(client)
 	resp, err := requestCommand.Execute()
 	if err != nil {
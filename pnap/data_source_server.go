@@ -1,281 +1,308 @@
 package pnap
 
 import (
-	"fmt"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 )
 
 func dataSourceServer() *schema.Resource {
+	serverSchema := serverDataSchema()
+	serverSchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"hostname"},
+	}
+	serverSchema["hostname"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
+	serverSchema["hostname_regex"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ValidateFunc:  validation.StringIsValidRegExp,
+		ConflictsWith: []string{"id", "hostname"},
+	}
+	serverSchema["most_recent"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
 	return &schema.Resource{
 
-		Read: dataSourceServerRead,
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"hostname"},
-			},
-			"hostname": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"id"},
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		Read:   dataSourceServerRead,
+		Schema: serverSchema,
+	}
+}
 
-			"primary_ip_address": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+// serverDataSchema returns the computed attributes of a server as read by the server data sources.
+func serverDataSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"hostname": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
 
-			"private_ip_addresses": {
-				Type:     schema.TypeSet,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"public_ip_addresses": {
-				Type:     schema.TypeSet,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"os": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"location": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"network_type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"bring_your_own_license": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"esxi": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"datastore_configuration": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"datastore_name": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
+		"primary_ip_address": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"private_ip_addresses": {
+			Type:     schema.TypeSet,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"public_ip_addresses": {
+			Type:     schema.TypeSet,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"os": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"network_type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"bring_your_own_license": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"esxi": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"datastore_configuration": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"datastore_name": {
+									Type:     schema.TypeString,
+									Computed: true,
 								},
 							},
 						},
 					},
 				},
 			},
-			"ipxe": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"url": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"native_vlan_configuration": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"vlan_id": {
-										Type:     schema.TypeInt,
-										Computed: true,
-									},
-									"static_dhcp_address_v4": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
-									"status": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
+		},
+		"ipxe": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"url": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"native_vlan_configuration": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"vlan_id": {
+									Type:     schema.TypeInt,
+									Computed: true,
+								},
+								"static_dhcp_address_v4": {
+									Type:     schema.TypeString,
+									Computed: true,
+								},
+								"status": {
+									Type:     schema.TypeString,
+									Computed: true,
 								},
 							},
 						},
 					},
 				},
 			},
-			"netris_controller": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"host_os": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+		},
+		"netris_controller": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"host_os": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"netris_softgate": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"host_os": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+		},
+		"netris_softgate": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"host_os": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"tags": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"name": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"value": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"is_billing_tag": {
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"created_by": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+		},
+		"tags": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"is_billing_tag": {
+						Type:     schema.TypeBool,
+						Computed: true,
+					},
+					"created_by": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"network_configuration": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"gateway_address": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"private_network_configuration": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"configuration_type": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
-									"private_networks": {
-										Type:     schema.TypeList,
-										Computed: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"id": {
-													Type:     schema.TypeString,
-													Computed: true,
-												},
-												"ips": {
-													Type:     schema.TypeSet,
-													Computed: true,
-													Elem:     &schema.Schema{Type: schema.TypeString},
-												},
-												"dhcp": {
-													Type:     schema.TypeBool,
-													Computed: true,
-												},
-												"status_description": {
-													Type:     schema.TypeString,
-													Computed: true,
-												},
-												"vlan_id": {
-													Type:     schema.TypeInt,
-													Computed: true,
-												},
+		},
+		"network_configuration": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"gateway_address": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"private_network_configuration": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"configuration_type": {
+									Type:     schema.TypeString,
+									Computed: true,
+								},
+								"private_networks": {
+									Type:     schema.TypeList,
+									Computed: true,
+									Elem: &schema.Resource{
+										Schema: map[string]*schema.Schema{
+											"id": {
+												Type:     schema.TypeString,
+												Computed: true,
+											},
+											"ips": {
+												Type:     schema.TypeSet,
+												Computed: true,
+												Elem:     &schema.Schema{Type: schema.TypeString},
+											},
+											"dhcp": {
+												Type:     schema.TypeBool,
+												Computed: true,
+											},
+											"status_description": {
+												Type:     schema.TypeString,
+												Computed: true,
+											},
+											"vlan_id": {
+												Type:     schema.TypeInt,
+												Computed: true,
 											},
 										},
 									},
 								},
 							},
 						},
-						"ip_blocks_configuration": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"configuration_type": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
-									"ip_blocks": {
-										Type:     schema.TypeList,
-										Computed: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"id": {
-													Type:     schema.TypeString,
-													Computed: true,
-												},
-												"vlan_id": {
-													Type:     schema.TypeInt,
-													Computed: true,
-												},
+					},
+					"ip_blocks_configuration": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"configuration_type": {
+									Type:     schema.TypeString,
+									Computed: true,
+								},
+								"ip_blocks": {
+									Type:     schema.TypeList,
+									Computed: true,
+									Elem: &schema.Resource{
+										Schema: map[string]*schema.Schema{
+											"id": {
+												Type:     schema.TypeString,
+												Computed: true,
+											},
+											"vlan_id": {
+												Type:     schema.TypeInt,
+												Computed: true,
 											},
 										},
 									},
 								},
 							},
 						},
-						"public_network_configuration": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"public_networks": {
-										Type:     schema.TypeList,
-										Computed: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"id": {
-													Type:     schema.TypeString,
-													Computed: true,
-												},
-												"ips": {
-													Type:     schema.TypeSet,
-													Computed: true,
-													Elem:     &schema.Schema{Type: schema.TypeString},
-												},
-												"status_description": {
-													Type:     schema.TypeString,
-													Computed: true,
-												},
-												"vlan_id": {
-													Type:     schema.TypeInt,
-													Computed: true,
-												},
+					},
+					"public_network_configuration": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"public_networks": {
+									Type:     schema.TypeList,
+									Computed: true,
+									Elem: &schema.Resource{
+										Schema: map[string]*schema.Schema{
+											"id": {
+												Type:     schema.TypeString,
+												Computed: true,
+											},
+											"ips": {
+												Type:     schema.TypeSet,
+												Computed: true,
+												Elem:     &schema.Schema{Type: schema.TypeString},
+											},
+											"status_description": {
+												Type:     schema.TypeString,
+												Computed: true,
+											},
+											"compute_slaac_ip": {
+												Type:     schema.TypeBool,
+												Computed: true,
+											},
+											"vlan_id": {
+												Type:     schema.TypeInt,
+												Computed: true,
 											},
 										},
 									},
@@ -285,202 +312,212 @@ func dataSourceServer() *schema.Resource {
 					},
 				},
 			},
-			"storage_configuration": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"root_partition": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"raid": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
-									"size": {
-										Type:     schema.TypeInt,
-										Computed: true,
-									},
+		},
+		"storage_configuration": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"root_partition": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"raid": {
+									Type:     schema.TypeString,
+									Computed: true,
+								},
+								"size": {
+									Type:     schema.TypeInt,
+									Computed: true,
 								},
 							},
 						},
 					},
 				},
 			},
-			"gpu_configuration": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"long_name": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"count": {
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
+		},
+		"hardware": serverHardwareSchema(),
+		"gpu_configuration": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"long_name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"count": {
+						Type:     schema.TypeInt,
+						Computed: true,
 					},
 				},
 			},
-			"superseded_by": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"supersedes": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		},
+		"superseded_by": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"supersedes": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
 func dataSourceServerRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
-	//serverID := d.Id()
+	client := m.(*providerMeta).client
 	requestCommand := server.NewGetServersCommand(client)
-	//requestCommand.SetRequester(client)
 	resp, err := requestCommand.Execute()
 	if err != nil {
 		return err
 	}
-	/* code := resp.StatusCode
-	if code != 200 {
-		response := &dto.ErrorMessage{}
-		response.FromBytes(resp)
-		return fmt.Errorf("API Returned Code: %v, Message: %v, Validation Errors: %v", code, response.Message, response.ValidationErrors)
+
+	filter, err := newLookupFilter(d, "server", "hostname")
+	if err != nil {
+		return err
+	}
+	instance, err := lookupOne(filter, resp, func(instance bmcapi.Server) lookupItem {
+		item := lookupItem{id: instance.Id, name: instance.Hostname}
+		if instance.ProvisionedOn != nil {
+			item.createdOn = *instance.ProvisionedOn
+		}
+		return item
+	})
+	if err != nil {
+		return err
 	}
-	response := &dto.Servers{}
-	response.FromBytes(resp) */
 
-	numOfServers := 0
-	for _, instance := range resp {
-		if instance.Hostname == d.Get("hostname").(string) || instance.Id == d.Get("id").(string) {
-			numOfServers++
-			d.SetId(instance.Id)
-			d.Set("status", instance.Status)
-			d.Set("hostname", instance.Hostname)
+	d.SetId(instance.Id)
+	serverItem := flattenServerData(instance)
+	delete(serverItem, "id")
+	for k, v := range serverItem {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			d.Set("os", instance.Os)
-			d.Set("type", instance.Type)
-			d.Set("location", instance.Location)
-			d.Set("network_type", instance.NetworkType)
+// Returns server details in the format of serverDataSchema
+func flattenServerData(instance bmcapi.Server) map[string]interface{} {
+	serverItem := make(map[string]interface{})
+	serverItem["id"] = instance.Id
+	serverItem["status"] = instance.Status
+	serverItem["hostname"] = instance.Hostname
+	if instance.Os != nil {
+		serverItem["os"] = *instance.Os
+	}
+	serverItem["type"] = instance.Type
+	serverItem["location"] = instance.Location
+	if instance.NetworkType != nil {
+		serverItem["network_type"] = *instance.NetworkType
+	}
 
-			var privateIPs []interface{}
-			for _, v := range instance.PrivateIpAddresses {
-				privateIPs = append(privateIPs, v)
-			}
-			d.Set("private_ip_addresses", privateIPs)
-			var publicIPs []interface{}
-			for _, k := range instance.PublicIpAddresses {
-				publicIPs = append(publicIPs, k)
-			}
-			d.Set("public_ip_addresses", publicIPs)
-			if len(instance.PublicIpAddresses) > 0 {
-				d.Set("primary_ip_address", instance.PublicIpAddresses[0])
-			}
+	var privateIPs []interface{}
+	for _, v := range instance.PrivateIpAddresses {
+		privateIPs = append(privateIPs, v)
+	}
+	serverItem["private_ip_addresses"] = privateIPs
+	var publicIPs []interface{}
+	for _, k := range instance.PublicIpAddresses {
+		publicIPs = append(publicIPs, k)
+	}
+	serverItem["public_ip_addresses"] = publicIPs
+	if len(instance.PublicIpAddresses) > 0 {
+		serverItem["primary_ip_address"] = instance.PublicIpAddresses[0]
+	}
 
-			if instance.OsConfiguration != nil {
-				if instance.OsConfiguration.Esxi != nil && instance.OsConfiguration.Esxi.DatastoreConfiguration != nil {
-					esxi := make([]interface{}, 1)
-					esxiItem := make(map[string]interface{})
-					datastoreConfiguration := make([]interface{}, 1)
-					datastoreConfigurationItem := make(map[string]interface{})
-					datastoreConfigurationItem["datastore_name"] = instance.OsConfiguration.Esxi.DatastoreConfiguration.DatastoreName
-					datastoreConfiguration[0] = datastoreConfigurationItem
-					esxiItem["datastore_configuration"] = datastoreConfiguration
-					esxi[0] = esxiItem
-					d.Set("esxi", esxi)
-				}
-				if instance.OsConfiguration.IPXE != nil {
-					iPXE := make([]interface{}, 1)
-					iPXEItem := make(map[string]interface{})
-					iPXEItem["url"] = instance.OsConfiguration.IPXE.Url
-					nativeVlanConfResp := instance.OsConfiguration.IPXE.NativeVlanConfiguration
-					if nativeVlanConfResp != nil {
-						nativeVlanConf := make([]interface{}, 1)
-						nativeVlanConfItem := make(map[string]interface{})
-						if nativeVlanConfResp.VlanId != nil {
-							nativeVlanConfItem["vlan_id"] = int(*nativeVlanConfResp.VlanId)
-						}
-						if nativeVlanConfResp.StaticDhcpAddressV4 != nil {
-							nativeVlanConfItem["static_dhcp_address_v4"] = *nativeVlanConfResp.StaticDhcpAddressV4
-						}
-						if nativeVlanConfResp.Status != nil {
-							nativeVlanConfItem["status"] = *nativeVlanConfResp.Status
-						}
-						nativeVlanConf[0] = nativeVlanConfItem
-						iPXEItem["native_vlan_configuration"] = nativeVlanConf
-					}
-					iPXE[0] = iPXEItem
-					d.Set("ipxe", iPXE)
-				}
-				if instance.OsConfiguration.NetrisController != nil {
-					netrisController := make([]interface{}, 1)
-					netrisControllerItem := make(map[string]interface{})
-					if instance.OsConfiguration.NetrisController.HostOs != nil {
-						netrisControllerItem["host_os"] = *instance.OsConfiguration.NetrisController.HostOs
-					}
-					netrisController[0] = netrisControllerItem
-					d.Set("netris_controller", netrisController)
+	if instance.OsConfiguration != nil {
+		if instance.OsConfiguration.Esxi != nil && instance.OsConfiguration.Esxi.DatastoreConfiguration != nil {
+			esxi := make([]interface{}, 1)
+			esxiItem := make(map[string]interface{})
+			datastoreConfiguration := make([]interface{}, 1)
+			datastoreConfigurationItem := make(map[string]interface{})
+			datastoreConfigurationItem["datastore_name"] = instance.OsConfiguration.Esxi.DatastoreConfiguration.DatastoreName
+			datastoreConfiguration[0] = datastoreConfigurationItem
+			esxiItem["datastore_configuration"] = datastoreConfiguration
+			esxi[0] = esxiItem
+			serverItem["esxi"] = esxi
+		}
+		if instance.OsConfiguration.IPXE != nil {
+			iPXE := make([]interface{}, 1)
+			iPXEItem := make(map[string]interface{})
+			iPXEItem["url"] = instance.OsConfiguration.IPXE.Url
+			nativeVlanConfResp := instance.OsConfiguration.IPXE.NativeVlanConfiguration
+			if nativeVlanConfResp != nil {
+				nativeVlanConf := make([]interface{}, 1)
+				nativeVlanConfItem := make(map[string]interface{})
+				if nativeVlanConfResp.VlanId != nil {
+					nativeVlanConfItem["vlan_id"] = int(*nativeVlanConfResp.VlanId)
 				}
-				if instance.OsConfiguration.NetrisSoftgate != nil {
-					netrisSoftgate := make([]interface{}, 1)
-					netrisSoftgateItem := make(map[string]interface{})
-					if instance.OsConfiguration.NetrisSoftgate.HostOs != nil {
-						netrisSoftgateItem["host_os"] = *instance.OsConfiguration.NetrisSoftgate.HostOs
-					}
-					netrisSoftgate[0] = netrisSoftgateItem
-					d.Set("netris_softgate", netrisSoftgate)
+				if nativeVlanConfResp.StaticDhcpAddressV4 != nil {
+					nativeVlanConfItem["static_dhcp_address_v4"] = *nativeVlanConfResp.StaticDhcpAddressV4
 				}
-				if instance.OsConfiguration.Windows != nil {
-					d.Set("bring_your_own_license", instance.OsConfiguration.Windows.BringYourOwnLicense)
+				if nativeVlanConfResp.Status != nil {
+					nativeVlanConfItem["status"] = *nativeVlanConfResp.Status
 				}
+				nativeVlanConf[0] = nativeVlanConfItem
+				iPXEItem["native_vlan_configuration"] = nativeVlanConf
 			}
-
-			tags := flattenServerDataTags(instance.Tags)
-			if err := d.Set("tags", tags); err != nil {
-				return err
-			}
-			netConf := flattenServerDataNetworkConfiguration(instance.NetworkConfiguration)
-			if err := d.Set("network_configuration", netConf); err != nil {
-				return err
-			}
-			if instance.StorageConfiguration.RootPartition != nil {
-				storageConfiguration := make([]interface{}, 1)
-				storageConfigurationItem := make(map[string]interface{})
-				rootPartition := make([]interface{}, 1)
-				rootPartitionItem := make(map[string]interface{})
-				if instance.StorageConfiguration.RootPartition.Raid != nil {
-					rootPartitionItem["raid"] = *instance.StorageConfiguration.RootPartition.Raid
-				}
-				if instance.StorageConfiguration.RootPartition.Size != nil {
-					rootPartitionItem["size"] = int(*instance.StorageConfiguration.RootPartition.Size)
-				}
-				rootPartition[0] = rootPartitionItem
-				storageConfigurationItem["root_partition"] = rootPartition
-				storageConfiguration[0] = storageConfigurationItem
-				d.Set("storage_configuration", storageConfiguration)
+			iPXE[0] = iPXEItem
+			serverItem["ipxe"] = iPXE
+		}
+		if instance.OsConfiguration.NetrisController != nil {
+			netrisController := make([]interface{}, 1)
+			netrisControllerItem := make(map[string]interface{})
+			if instance.OsConfiguration.NetrisController.HostOs != nil {
+				netrisControllerItem["host_os"] = *instance.OsConfiguration.NetrisController.HostOs
 			}
-			var gpuConf bmcapi.GpuConfiguration
-			if instance.GpuConfiguration != nil {
-				gpuConf = *instance.GpuConfiguration
+			netrisController[0] = netrisControllerItem
+			serverItem["netris_controller"] = netrisController
+		}
+		if instance.OsConfiguration.NetrisSoftgate != nil {
+			netrisSoftgate := make([]interface{}, 1)
+			netrisSoftgateItem := make(map[string]interface{})
+			if instance.OsConfiguration.NetrisSoftgate.HostOs != nil {
+				netrisSoftgateItem["host_os"] = *instance.OsConfiguration.NetrisSoftgate.HostOs
 			}
-			gpuConfiguration := flattenGpuConfiguration(gpuConf)
-			d.Set("gpu_configuration", gpuConfiguration)
-
-			d.Set("superseded_by", instance.SupersededBy)
-			d.Set("supersedes", instance.Supersedes)
+			netrisSoftgate[0] = netrisSoftgateItem
+			serverItem["netris_softgate"] = netrisSoftgate
+		}
+		if instance.OsConfiguration.Windows != nil && instance.OsConfiguration.Windows.BringYourOwnLicense != nil {
+			serverItem["bring_your_own_license"] = *instance.OsConfiguration.Windows.BringYourOwnLicense
 		}
 	}
 
-	if numOfServers > 1 {
-		return fmt.Errorf("too many devices found with hostname %s (found %d, expected 1)", d.Get("hostname").(string), numOfServers)
+	serverItem["tags"] = flattenServerDataTags(instance.Tags)
+	serverItem["network_configuration"] = flattenServerDataNetworkConfiguration(instance.NetworkConfiguration)
+	if instance.StorageConfiguration.RootPartition != nil {
+		storageConfiguration := make([]interface{}, 1)
+		storageConfigurationItem := make(map[string]interface{})
+		rootPartition := make([]interface{}, 1)
+		rootPartitionItem := make(map[string]interface{})
+		if instance.StorageConfiguration.RootPartition.Raid != nil {
+			rootPartitionItem["raid"] = *instance.StorageConfiguration.RootPartition.Raid
+		}
+		if instance.StorageConfiguration.RootPartition.Size != nil {
+			rootPartitionItem["size"] = int(*instance.StorageConfiguration.RootPartition.Size)
+		}
+		rootPartition[0] = rootPartitionItem
+		storageConfigurationItem["root_partition"] = rootPartition
+		storageConfiguration[0] = storageConfigurationItem
+		serverItem["storage_configuration"] = storageConfiguration
 	}
+	var gpuConf bmcapi.GpuConfiguration
+	if instance.GpuConfiguration != nil {
+		gpuConf = *instance.GpuConfiguration
+	}
+	serverItem["gpu_configuration"] = flattenGpuConfiguration(gpuConf)
+	serverItem["hardware"] = flattenServerHardware(instance)
 
-	return nil
+	if instance.SupersededBy != nil {
+		serverItem["superseded_by"] = *instance.SupersededBy
+	}
+	if instance.Supersedes != nil {
+		serverItem["supersedes"] = *instance.Supersedes
+	}
+	return serverItem
 }
 
 // Returns list of assigned tags
@@ -591,6 +628,9 @@ func flattenServerDataNetworkConfiguration(networkConfiguration bmcapi.NetworkCo
 				if j.StatusDescription != nil {
 					spn["status_description"] = *j.StatusDescription
 				}
+				if j.ComputeSlaacIp != nil {
+					spn["compute_slaac_ip"] = *j.ComputeSlaacIp
+				}
 				if j.VlanId != nil {
 					spn["vlan_id"] = *j.VlanId
 				}
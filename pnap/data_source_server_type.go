@@ -0,0 +1,194 @@
+package pnap
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/product"
+	"github.com/PNAP/go-sdk-helper-bmc/dto"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceServerType() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceServerTypeRead,
+
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"min_cores": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"min_ram_in_gb": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"min_gpu_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pricing_model": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "HOURLY",
+			},
+			"require_availability": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cpu": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"total_cores": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"ram_in_gb": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"gpu_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"storage": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"price": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"price_unit": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// serverTypeCandidate is a server product matching the hardware requirements of a pnap_server_type lookup.
+type serverTypeCandidate struct {
+	productCode string
+	cpu         string
+	totalCores  int
+	ramInGb     int
+	gpuCount    int
+	storage     string
+	price       float32
+	priceUnit   string
+}
+
+func dataSourceServerTypeRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	location := d.Get("location").(string)
+	pricingModel := d.Get("pricing_model").(string)
+
+	query := dto.ProductQuery{}
+	query.ProductCategory = "SERVER"
+	query.Location = location
+	requestCommand := product.NewGetProductsCommand(client, query)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+
+	var candidates []serverTypeCandidate
+	for _, j := range resp {
+		metadata := j.Metadata
+		candidate := serverTypeCandidate{
+			productCode: j.ProductCode,
+			cpu:         metadata.Cpu,
+			totalCores:  int(metadata.CpuCount * metadata.CoresPerCpu),
+			ramInGb:     int(metadata.RamInGb),
+			storage:     metadata.Storage,
+			price:       -1,
+		}
+		for _, l := range metadata.GpuConfigurations {
+			if l.Count != nil {
+				candidate.gpuCount += int(*l.Count)
+			}
+		}
+		if candidate.totalCores < d.Get("min_cores").(int) || candidate.ramInGb < d.Get("min_ram_in_gb").(int) ||
+			candidate.gpuCount < d.Get("min_gpu_count").(int) {
+			continue
+		}
+		for _, l := range j.Plans {
+			if l.Location == location && l.PricingModel == pricingModel {
+				candidate.price = l.Price
+				candidate.priceUnit = string(l.PriceUnit)
+				break
+			}
+		}
+		if candidate.price < 0 {
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if d.Get("require_availability").(bool) && len(candidates) > 0 {
+		availabilityQuery := dto.ProductAvailabilityQuery{}
+		availabilityQuery.ProductCategory = []string{"SERVER"}
+		for _, candidate := range candidates {
+			availabilityQuery.ProductCode = append(availabilityQuery.ProductCode, candidate.productCode)
+		}
+		availabilityQuery.Location = []string{location}
+		availabilityQuery.MinQuantity = 1
+		availabilityCommand := product.NewGetProductAvailabilityCommand(client, availabilityQuery)
+		availabilityResp, err := availabilityCommand.Execute()
+		if err != nil {
+			return err
+		}
+		available := make(map[string]bool)
+		for _, j := range availabilityResp {
+			for _, l := range j.LocationAvailabilityDetails {
+				if string(l.Location) == location && l.MinQuantityAvailable {
+					available[j.ProductCode] = true
+				}
+			}
+		}
+		var availableCandidates []serverTypeCandidate
+		for _, candidate := range candidates {
+			if available[candidate.productCode] {
+				availableCandidates = append(availableCandidates, candidate)
+			}
+		}
+		candidates = availableCandidates
+	}
+
+	if len(candidates) == 0 {
+		return fmt.Errorf("no server type found in %s with %s pricing and at least %d cores, %d GB RAM and %d GPUs",
+			location, pricingModel, d.Get("min_cores").(int), d.Get("min_ram_in_gb").(int), d.Get("min_gpu_count").(int))
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].price != candidates[j].price {
+			return candidates[i].price < candidates[j].price
+		}
+		return candidates[i].productCode < candidates[j].productCode
+	})
+	cheapest := candidates[0]
+
+	d.SetId(cheapest.productCode)
+	d.Set("type", cheapest.productCode)
+	d.Set("cpu", cheapest.cpu)
+	d.Set("total_cores", cheapest.totalCores)
+	d.Set("ram_in_gb", cheapest.ramInGb)
+	d.Set("gpu_count", cheapest.gpuCount)
+	d.Set("storage", cheapest.storage)
+	d.Set("price", customRound(float64(cheapest.price)))
+	d.Set("price_unit", cheapest.priceUnit)
+	return nil
+}
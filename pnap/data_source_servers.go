@@ -0,0 +1,141 @@
+package pnap
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+func dataSourceServers() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceServersRead,
+
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"os": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tag_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tag_value": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"tag_name"},
+			},
+			"hostname_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"has_reservation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"servers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: serverDataSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServersRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	requestCommand := server.NewGetServersCommand(client)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+
+	var hostnameRegex *regexp.Regexp
+	if v := d.Get("hostname_regex").(string); len(v) > 0 {
+		hostnameRegex, err = regexp.Compile(v)
+		if err != nil {
+			return err
+		}
+	}
+	reservationFilter := d.GetRawConfig().GetAttr("has_reservation")
+
+	ids := make([]interface{}, 0)
+	servers := make([]interface{}, 0)
+	for _, instance := range resp {
+		if v := d.Get("location").(string); len(v) > 0 && instance.Location != v {
+			continue
+		}
+		if v := d.Get("type").(string); len(v) > 0 && instance.Type != v {
+			continue
+		}
+		if v := d.Get("status").(string); len(v) > 0 && instance.Status != v {
+			continue
+		}
+		if v := d.Get("os").(string); len(v) > 0 && (instance.Os == nil || *instance.Os != v) {
+			continue
+		}
+		if v := d.Get("tag_name").(string); len(v) > 0 && !serverHasTag(instance, v, d.GetRawConfig().GetAttr("tag_value").IsNull(), d.Get("tag_value").(string)) {
+			continue
+		}
+		if hostnameRegex != nil && !hostnameRegex.MatchString(instance.Hostname) {
+			continue
+		}
+		if !reservationFilter.IsNull() {
+			hasReservation := instance.ReservationId != nil && len(*instance.ReservationId) > 0
+			if hasReservation != d.Get("has_reservation").(bool) {
+				continue
+			}
+		}
+		ids = append(ids, instance.Id)
+		servers = append(servers, flattenServerData(instance))
+	}
+
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	d.Set("ids", ids)
+	if err := d.Set("servers", servers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// serverHasTag checks whether the tag is assigned to the server, with the given value unless anyValue is set.
+func serverHasTag(instance bmcapi.Server, name string, anyValue bool, value string) bool {
+	for _, tag := range instance.Tags {
+		if tag.Name != name {
+			continue
+		}
+		if anyValue {
+			return true
+		}
+		tagValue := ""
+		if tag.Value != nil {
+			tagValue = *tag.Value
+		}
+		return tagValue == value
+	}
+	return false
+}
@@ -1,11 +1,10 @@
 package pnap
 
 import (
-	"fmt"
-
 	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/sshkey"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
 )
 
 func dataSourceSshKey() *schema.Resource {
@@ -29,6 +28,17 @@ func dataSourceSshKey() *schema.Resource {
 				Computed:      true,
 				ConflictsWith: []string{"id"},
 			},
+			"name_regex": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringIsValidRegExp,
+				ConflictsWith: []string{"id", "name"},
+			},
+			"most_recent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"key": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -38,7 +48,7 @@ func dataSourceSshKey() *schema.Resource {
 }
 
 func dataSourceSshKeyRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	requestCommand := sshkey.NewGetSshKeysCommand(client)
 	resp, err := requestCommand.Execute()
 	if err != nil {
@@ -53,20 +63,21 @@ func dataSourceSshKeyRead(d *schema.ResourceData, m interface{}) error {
 	response := &dto.SshKeys{}
 	response.FromBytes(resp) */
 
-	numOfKeys := 0
-	for _, instance := range resp {
-		if instance.Name == d.Get("name").(string) || instance.Id == d.Get("id").(string) {
-			numOfKeys++
-			d.SetId(instance.Id)
-			d.Set("default", instance.Default)
-			d.Set("name", instance.Name)
-			d.Set("key", instance.Key)
-
-		}
+	filter, err := newLookupFilter(d, "SSH key", "name")
+	if err != nil {
+		return err
 	}
-	if numOfKeys > 1 {
-		return fmt.Errorf("too many ssh keys with name %s (found %d, expected 1)", d.Get("name").(string), numOfKeys)
+	instance, err := lookupOne(filter, resp, func(instance bmcapiclient.SshKey) lookupItem {
+		return lookupItem{id: instance.Id, name: instance.Name, createdOn: instance.CreatedOn}
+	})
+	if err != nil {
+		return err
 	}
 
+	d.SetId(instance.Id)
+	d.Set("default", instance.Default)
+	d.Set("name", instance.Name)
+	d.Set("key", instance.Key)
+
 	return nil
 }
@@ -1,12 +1,10 @@
 package pnap
 
 import (
-	"fmt"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkstorageapi/storagenetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	networkstorageapiclient "github.com/phoenixnap/go-sdk-bmc/networkstorageapi/v3"
 )
 
@@ -28,6 +26,17 @@ func dataSourceStorageNetwork() *schema.Resource {
 				Computed:      true,
 				ConflictsWith: []string{"id"},
 			},
+			"name_regex": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringIsValidRegExp,
+				ConflictsWith: []string{"id", "name"},
+			},
+			"most_recent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"location": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -183,53 +192,67 @@ func dataSourceStorageNetwork() *schema.Resource {
 }
 
 func dataSourceStorageNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	requestCommand := storagenetwork.NewGetStorageNetworksCommand(client)
 	resp, err := requestCommand.Execute()
 	if err != nil {
 		return err
 	}
 
-	numOfStorageNets := 0
-	for _, instance := range resp {
-		if instance.Name != nil && *instance.Name == d.Get("name").(string) || instance.Id != nil && *instance.Id == d.Get("id").(string) {
-			numOfStorageNets++
-			d.SetId(*instance.Id)
-			d.Set("name", *instance.Name)
-			if instance.Description != nil {
-				d.Set("description", *instance.Description)
-			}
-			if instance.Status != nil {
-				d.Set("status", *instance.Status)
-			}
-			if instance.Location != nil {
-				d.Set("location", *instance.Location)
-			}
-			if instance.NetworkId != nil {
-				d.Set("network_id", *instance.NetworkId)
-			}
-			var ips []interface{}
-			for _, v := range instance.Ips {
-				ips = append(ips, v)
-			}
-			d.Set("ips", ips)
-			if instance.CreatedOn != nil {
-				createdOn := *instance.CreatedOn
-				d.Set("created_on", createdOn.String())
-			}
-			if instance.DeleteRequestedOn != nil {
-				delReqOn := *instance.DeleteRequestedOn
-				d.Set("delete_requested_on", delReqOn.String())
-			}
-			volumes := flattenDataVolumes(instance.Volumes)
-
-			if err := d.Set("volumes", volumes); err != nil {
-				return err
-			}
+	filter, err := newLookupFilter(d, "storage network", "name")
+	if err != nil {
+		return err
+	}
+	instance, err := lookupOne(filter, resp, func(instance networkstorageapiclient.StorageNetwork) lookupItem {
+		item := lookupItem{}
+		if instance.Id != nil {
+			item.id = *instance.Id
+		}
+		if instance.Name != nil {
+			item.name = *instance.Name
 		}
+		if instance.CreatedOn != nil {
+			item.createdOn = *instance.CreatedOn
+		}
+		return item
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(*instance.Id)
+	if instance.Name != nil {
+		d.Set("name", *instance.Name)
+	}
+	if instance.Description != nil {
+		d.Set("description", *instance.Description)
 	}
-	if numOfStorageNets > 1 {
-		return fmt.Errorf("too many storage networks with name %s (found %d, expected 1)", d.Get("name").(string), numOfStorageNets)
+	if instance.Status != nil {
+		d.Set("status", *instance.Status)
+	}
+	if instance.Location != nil {
+		d.Set("location", *instance.Location)
+	}
+	if instance.NetworkId != nil {
+		d.Set("network_id", *instance.NetworkId)
+	}
+	var ips []interface{}
+	for _, v := range instance.Ips {
+		ips = append(ips, v)
+	}
+	d.Set("ips", ips)
+	if instance.CreatedOn != nil {
+		createdOn := *instance.CreatedOn
+		d.Set("created_on", createdOn.String())
+	}
+	if instance.DeleteRequestedOn != nil {
+		delReqOn := *instance.DeleteRequestedOn
+		d.Set("delete_requested_on", delReqOn.String())
+	}
+	volumes := flattenDataVolumes(instance.Volumes)
+
+	if err := d.Set("volumes", volumes); err != nil {
+		return err
 	}
 	return nil
 }
@@ -1,11 +1,10 @@
 package pnap
 
 import (
-	"fmt"
-
 	"github.com/PNAP/go-sdk-helper-bmc/command/tagapi/tag"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	tagapiclient "github.com/phoenixnap/go-sdk-bmc/tagapi/v3"
 )
 
 func dataSourceTag() *schema.Resource {
@@ -25,6 +24,12 @@ func dataSourceTag() *schema.Resource {
 				Computed:      true,
 				ConflictsWith: []string{"id"},
 			},
+			"name_regex": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringIsValidRegExp,
+				ConflictsWith: []string{"id", "name"},
+			},
 			"values": {
 				Type:     schema.TypeSet,
 				Computed: true,
@@ -63,50 +68,52 @@ func dataSourceTag() *schema.Resource {
 }
 
 func dataSourceTagRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	requestCommand := tag.NewGetTagsCommand(client)
 	resp, err := requestCommand.Execute()
 	if err != nil {
 		return err
 	}
-	numOfTags := 0
-	for _, instance := range resp {
-		if instance.Name == d.Get("name").(string) || instance.Id == d.Get("id").(string) {
-			numOfTags++
-			d.SetId(instance.Id)
-			d.Set("name", instance.Name)
-			if instance.Values != nil {
-				readValues := instance.Values
-				var values []interface{}
-				for _, v := range readValues {
-					values = append(values, v)
-				}
-				d.Set("values", values)
-			}
-			if instance.Description != nil {
-				d.Set("description", *instance.Description)
-			}
-			d.Set("is_billing_tag", instance.IsBillingTag)
-			if instance.ResourceAssignments != nil {
-				readAssigns := instance.ResourceAssignments
-				assigns := make([]interface{}, len(readAssigns))
-				for i, a := range readAssigns {
-					assign := make(map[string]interface{})
-					assign["resource_name"] = a.ResourceName
-					if a.Value != nil {
-						assign["value"] = *a.Value
-					}
-					assigns[i] = assign
-				}
-				d.Set("resource_assignments", assigns)
-			}
-			if instance.CreatedBy != nil {
-				d.Set("created_by", *instance.CreatedBy)
+	filter, err := newLookupFilter(d, "tag", "name")
+	if err != nil {
+		return err
+	}
+	instance, err := lookupOne(filter, resp, func(instance tagapiclient.Tag) lookupItem {
+		return lookupItem{id: instance.Id, name: instance.Name}
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(instance.Id)
+	d.Set("name", instance.Name)
+	if instance.Values != nil {
+		readValues := instance.Values
+		var values []interface{}
+		for _, v := range readValues {
+			values = append(values, v)
+		}
+		d.Set("values", values)
+	}
+	if instance.Description != nil {
+		d.Set("description", *instance.Description)
+	}
+	d.Set("is_billing_tag", instance.IsBillingTag)
+	if instance.ResourceAssignments != nil {
+		readAssigns := instance.ResourceAssignments
+		assigns := make([]interface{}, len(readAssigns))
+		for i, a := range readAssigns {
+			assign := make(map[string]interface{})
+			assign["resource_name"] = a.ResourceName
+			if a.Value != nil {
+				assign["value"] = *a.Value
 			}
+			assigns[i] = assign
 		}
+		d.Set("resource_assignments", assigns)
 	}
-	if numOfTags > 1 {
-		return fmt.Errorf("too many tags with name %s (found %d, expected 1)", d.Get("name").(string), numOfTags)
+	if instance.CreatedBy != nil {
+		d.Set("created_by", *instance.CreatedBy)
 	}
 	return nil
 }
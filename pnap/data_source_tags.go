@@ -0,0 +1,189 @@
+package pnap
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/tagapi/tag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceTags() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTagsRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"is_billing_tag": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"values": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_billing_tag": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"created_by": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource_assignments": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"resource_type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"resource_ids": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"assignments": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"resource_name": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"resource_id": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"value": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTagsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	requestCommand := tag.NewGetTagsCommand(client)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+
+	namePrefix := d.Get("name_prefix").(string)
+	value := d.Get("value").(string)
+	billingFilter := d.GetRawConfig().GetAttr("is_billing_tag")
+
+	var tags []interface{}
+	for _, instance := range resp {
+		if !strings.HasPrefix(instance.Name, namePrefix) {
+			continue
+		}
+		if !billingFilter.IsNull() && instance.IsBillingTag != d.Get("is_billing_tag").(bool) {
+			continue
+		}
+
+		// Assignments are grouped by resource type, keeping the order in which the types first appear.
+		var groups []interface{}
+		groupIndex := make(map[string]int)
+		for _, a := range instance.ResourceAssignments {
+			assignValue := ""
+			if a.Value != nil {
+				assignValue = *a.Value
+			}
+			if len(value) > 0 && assignValue != value {
+				continue
+			}
+			resourceType, resourceID := parseTagResourceName(a.ResourceName)
+			assign := make(map[string]interface{})
+			assign["resource_name"] = a.ResourceName
+			assign["resource_id"] = resourceID
+			assign["value"] = assignValue
+
+			i, exists := groupIndex[resourceType]
+			if !exists {
+				i = len(groups)
+				groupIndex[resourceType] = i
+				groups = append(groups, map[string]interface{}{
+					"resource_type": resourceType,
+					"resource_ids":  []interface{}{},
+					"assignments":   []interface{}{},
+				})
+			}
+			group := groups[i].(map[string]interface{})
+			group["resource_ids"] = append(group["resource_ids"].([]interface{}), resourceID)
+			group["assignments"] = append(group["assignments"].([]interface{}), assign)
+		}
+		if len(value) > 0 && len(groups) == 0 {
+			continue
+		}
+
+		tagMap := make(map[string]interface{})
+		tagMap["id"] = instance.Id
+		tagMap["name"] = instance.Name
+		var values []interface{}
+		for _, v := range instance.Values {
+			values = append(values, v)
+		}
+		tagMap["values"] = values
+		if instance.Description != nil {
+			tagMap["description"] = *instance.Description
+		}
+		tagMap["is_billing_tag"] = instance.IsBillingTag
+		if instance.CreatedBy != nil {
+			tagMap["created_by"] = *instance.CreatedBy
+		}
+		tagMap["resource_assignments"] = groups
+		tags = append(tags, tagMap)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	d.Set("tags", tags)
+	return nil
+}
+
+// parseTagResourceName splits a tag resource name, such as /bmc/servers/<id>, into the resource type and the resource ID.
+func parseTagResourceName(resourceName string) (string, string) {
+	parts := strings.Split(strings.Trim(resourceName, "/"), "/")
+	if len(parts) < 2 {
+		return "", resourceName
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/paymentsapi/transaction"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -138,7 +137,7 @@ func dataSourceTransactions() *schema.Resource {
 }
 
 func dataSourceTransactionsRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	query := dto.Query{}
 	query.Limit = int32(d.Get("limit").(int))
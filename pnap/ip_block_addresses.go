@@ -0,0 +1,118 @@
+package pnap
+
+import (
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
+	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+)
+
+// ipBlockMaxListedIps is the largest number of addresses listed in the usable and available addresses of an
+// IP Block, which keeps the state of large blocks, e.g. a bring your own /20 with 4093 usable addresses, small.
+const ipBlockMaxListedIps = 256
+
+// ipBlockAddresses holds the special and usable addresses of an IP Block.
+type ipBlockAddresses struct {
+	network     string
+	gateway     string
+	broadcast   string
+	firstUsable string
+	usable      []string
+}
+
+// computeIpBlockAddresses returns the addresses of an IP Block, given in CIDR notation, as single addresses. The first address of a block is
+// the network address, the second the gateway and the last the broadcast address; the remaining addresses are
+// usable. A /31 IPv4 block has a gateway and one usable address, a /32 block none. IPv6 blocks have no broadcast
+// address, and their usable addresses are not listed.
+func computeIpBlockAddresses(cidr string) (ipBlockAddresses, error) {
+	var addresses ipBlockAddresses
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return addresses, err
+	}
+	prefix = prefix.Masked()
+	first := prefix.Addr()
+
+	if first.Is6() {
+		addresses.network = first.String()
+		addresses.gateway = first.Next().String()
+		addresses.firstUsable = first.Next().Next().String()
+		return addresses, nil
+	}
+
+	if prefix.Bits() == 32 {
+		return addresses, nil
+	}
+	if prefix.Bits() == 31 {
+		addresses.gateway = first.String()
+		addresses.firstUsable = first.Next().String()
+		addresses.usable = []string{addresses.firstUsable}
+		return addresses, nil
+	}
+
+	last := first
+	for addr := first; prefix.Contains(addr); addr = addr.Next() {
+		last = addr
+	}
+	addresses.network = first.String()
+	addresses.gateway = first.Next().String()
+	addresses.broadcast = last.String()
+	for addr := first.Next().Next(); addr.Less(last); addr = addr.Next() {
+		addresses.usable = append(addresses.usable, addr.String())
+	}
+	if len(addresses.usable) > 0 {
+		addresses.firstUsable = addresses.usable[0]
+	}
+	return addresses, nil
+}
+
+// getIpBlockUsedIps returns the addresses of an IP Block in use by the server or the public network members
+// the block is assigned to. No addresses are returned while the assigned resource is not found, e.g. while it is
+// being deleted.
+func getIpBlockUsedIps(client receiver.BMCSDK, cidr string, assignedResourceID string, assignedResourceType string) ([]string, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil || len(assignedResourceID) == 0 {
+		return nil, err
+	}
+
+	var ips []string
+	switch {
+	case strings.EqualFold(assignedResourceType, "server"):
+		requestCommand := server.NewGetServerCommand(client, assignedResourceID)
+		resp, err := requestCommand.Execute()
+		if isNotFoundError(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		ips = resp.PublicIpAddresses
+	case strings.Contains(strings.ToLower(assignedResourceType), "network"):
+		requestCommand := publicnetwork.NewGetPublicNetworkCommand(client, assignedResourceID)
+		resp, err := requestCommand.Execute()
+		if isNotFoundError(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		for _, j := range resp.Memberships {
+			ips = append(ips, divideIpsRange(j.Ips)...)
+		}
+	}
+
+	var usedAddrs []netip.Addr
+	for _, ip := range removeDuplicateIps(ips) {
+		addr, err := netip.ParseAddr(ip)
+		if err == nil && prefix.Contains(addr) {
+			usedAddrs = append(usedAddrs, addr)
+		}
+	}
+	sort.Slice(usedAddrs, func(i, j int) bool { return usedAddrs[i].Less(usedAddrs[j]) })
+	usedIps := make([]string, len(usedAddrs))
+	for i, addr := range usedAddrs {
+		usedIps[i] = addr.String()
+	}
+	return usedIps, nil
+}
@@ -0,0 +1,74 @@
+package pnap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeIpBlockAddresses(t *testing.T) {
+	cases := []struct {
+		cidr     string
+		expected ipBlockAddresses
+	}{
+		{"182.16.0.144/29", ipBlockAddresses{
+			network:     "182.16.0.144",
+			gateway:     "182.16.0.145",
+			broadcast:   "182.16.0.151",
+			firstUsable: "182.16.0.146",
+			usable:      []string{"182.16.0.146", "182.16.0.147", "182.16.0.148", "182.16.0.149", "182.16.0.150"},
+		}},
+		{"182.16.0.148/30", ipBlockAddresses{
+			network:     "182.16.0.148",
+			gateway:     "182.16.0.149",
+			broadcast:   "182.16.0.151",
+			firstUsable: "182.16.0.150",
+			usable:      []string{"182.16.0.150"},
+		}},
+		// A host address is masked to the block.
+		{"182.16.0.150/30", ipBlockAddresses{
+			network:     "182.16.0.148",
+			gateway:     "182.16.0.149",
+			broadcast:   "182.16.0.151",
+			firstUsable: "182.16.0.150",
+			usable:      []string{"182.16.0.150"},
+		}},
+		{"182.16.0.150/31", ipBlockAddresses{
+			gateway:     "182.16.0.150",
+			firstUsable: "182.16.0.151",
+			usable:      []string{"182.16.0.151"},
+		}},
+		{"182.16.0.150/32", ipBlockAddresses{}},
+		{"2604:5c80:1000::/64", ipBlockAddresses{
+			network:     "2604:5c80:1000::",
+			gateway:     "2604:5c80:1000::1",
+			firstUsable: "2604:5c80:1000::2",
+		}},
+	}
+	for _, c := range cases {
+		actual, err := computeIpBlockAddresses(c.cidr)
+		if err != nil {
+			t.Errorf("computeIpBlockAddresses(%s) returned error: %v", c.cidr, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("computeIpBlockAddresses(%s) = %+v, expected %+v", c.cidr, actual, c.expected)
+		}
+	}
+
+	if _, err := computeIpBlockAddresses("182.16.0.150"); err == nil {
+		t.Errorf("computeIpBlockAddresses() of an address without prefix length returned no error")
+	}
+}
+
+func TestComputeIpBlockAddresses_largeBlock(t *testing.T) {
+	addresses, err := computeIpBlockAddresses("10.16.0.0/20")
+	if err != nil {
+		t.Fatalf("computeIpBlockAddresses() returned error: %v", err)
+	}
+	if len(addresses.usable) != 4093 || addresses.usable[0] != "10.16.0.2" || addresses.usable[len(addresses.usable)-1] != "10.16.15.254" {
+		t.Errorf("expected 4093 usable addresses from 10.16.0.2 to 10.16.15.254, got %d", len(addresses.usable))
+	}
+	if addresses.broadcast != "10.16.15.255" {
+		t.Errorf("expected broadcast address 10.16.15.255, got %s", addresses.broadcast)
+	}
+}
@@ -0,0 +1,120 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
+	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	ipVersionV4 = "V4"
+	ipVersionV6 = "V6"
+)
+
+// ipBlockPrefixLengths holds the shortest and longest prefix lengths of IP Blocks of each IP version that
+// IpBlockCreate.cidrBlockSize supports: /28 to /31 IPv4 blocks and /64 IPv6 blocks. Larger blocks are requested
+// from support.
+var ipBlockPrefixLengths = map[string][2]int{
+	ipVersionV4: {28, 31},
+	ipVersionV6: {64, 64},
+}
+
+// parseCidrBlockSize returns the prefix length of a CIDR block size, such as /28.
+func parseCidrBlockSize(size string) (int, error) {
+	prefixLength, err := strconv.ParseUint(strings.TrimPrefix(size, "/"), 10, 8)
+	if err != nil || !strings.HasPrefix(size, "/") {
+		return 0, fmt.Errorf("invalid cidr_block_size %q, expected a prefix length such as /28", size)
+	}
+	return int(prefixLength), nil
+}
+
+// customizeDiffIpBlockVersion checks during plan that the block size, or the CIDR of a bring your own block,
+// matches the IP version.
+func customizeDiffIpBlockVersion(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if len(d.Id()) > 0 {
+		return nil
+	}
+	rawIpVersion := d.GetRawConfig().GetAttr("ip_version")
+	if !rawIpVersion.IsKnown() {
+		return nil
+	}
+	ipVersion := ipVersionV4
+	if !rawIpVersion.IsNull() {
+		ipVersion = rawIpVersion.AsString()
+	}
+
+	if v := d.GetRawConfig().GetAttr("cidr"); !v.IsNull() {
+		if !v.IsKnown() || rawIpVersion.IsNull() {
+			return nil
+		}
+		prefix, err := netip.ParsePrefix(v.AsString())
+		if err != nil {
+			return err
+		}
+		if (ipVersion == ipVersionV6) != prefix.Addr().Is6() {
+			return fmt.Errorf("cidr %s does not match ip_version %s", prefix, ipVersion)
+		}
+		return nil
+	}
+
+	if !d.NewValueKnown("cidr_block_size") {
+		return nil
+	}
+	size := d.Get("cidr_block_size").(string)
+	prefixLength, err := parseCidrBlockSize(size)
+	if err != nil {
+		return err
+	}
+	prefixLengths := ipBlockPrefixLengths[ipVersion]
+	if prefixLength < prefixLengths[0] || prefixLength > prefixLengths[1] {
+		if prefixLengths[0] == prefixLengths[1] {
+			return fmt.Errorf("cidr_block_size %s is not supported for ip_version %s, expected /%d", size, ipVersion, prefixLengths[0])
+		}
+		return fmt.Errorf("cidr_block_size %s is not supported for ip_version %s, expected /%d to /%d", size, ipVersion, prefixLengths[0], prefixLengths[1])
+	}
+	return nil
+}
+
+// customizeDiffPublicNetworkRa checks during plan that router advertisement is enabled only on public networks
+// with an IPv6 block. Networks whose blocks are attached with pnap_public_network_ip_block are not checked.
+func customizeDiffPublicNetworkRa(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	rawIpBlocks := d.GetRawConfig().GetAttr("ip_blocks")
+	if !d.HasChanges("ra_enabled", "ip_blocks") || rawIpBlocks.IsNull() || !rawIpBlocks.IsWhollyKnown() {
+		return nil
+	}
+	if v := d.GetRawConfig().GetAttr("ra_enabled"); v.IsNull() || !v.IsKnown() || v.False() {
+		return nil
+	}
+	client := m.(*providerMeta).client
+	for _, j := range d.Get("ip_blocks").([]interface{}) {
+		ibItem := j.(map[string]interface{})
+		pnib := ibItem["public_network_ip_block"].([]interface{})
+		if len(pnib) == 0 || pnib[0] == nil {
+			continue
+		}
+		isV6, err := isIpv6Block(client, pnib[0].(map[string]interface{})["id"].(string))
+		if err != nil {
+			return err
+		}
+		if isV6 {
+			return nil
+		}
+	}
+	return fmt.Errorf("ra_enabled requires an IPv6 block in ip_blocks")
+}
+
+// isIpv6Block checks whether the IP Block is an IPv6 block.
+func isIpv6Block(client receiver.BMCSDK, ipBlockID string) (bool, error) {
+	requestCommand := ipblock.NewGetIpBlockCommand(client, ipBlockID)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return false, err
+	}
+	return resp.IpVersion != nil && *resp.IpVersion == ipVersionV6, nil
+}
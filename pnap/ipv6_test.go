@@ -0,0 +1,82 @@
+package pnap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseCidrBlockSize(t *testing.T) {
+	cases := []struct {
+		size     string
+		expected int
+		valid    bool
+	}{
+		{"/28", 28, true},
+		{"/31", 31, true},
+		{"/64", 64, true},
+		{"28", 0, false},
+		{"/", 0, false},
+		{"/+28", 0, false},
+		{"/-1", 0, false},
+		{"/28a", 0, false},
+		{"", 0, false},
+	}
+	for _, c := range cases {
+		actual, err := parseCidrBlockSize(c.size)
+		if valid := err == nil; valid != c.valid || actual != c.expected {
+			t.Errorf("parseCidrBlockSize(%q) = %d, %v, expected %d, valid %t", c.size, actual, err, c.expected, c.valid)
+		}
+	}
+}
+
+// diffIpBlock plans the creation of an IP Block with the configuration.
+func diffIpBlock(config map[string]string) error {
+	r := resourceIpBlock()
+	raw := make(map[string]interface{})
+	attributes := make(map[string]cty.Value)
+	for k, v := range config {
+		raw[k] = v
+		attributes[k] = cty.StringVal(v)
+	}
+	rawConfig, err := r.CoreConfigSchema().CoerceValue(cty.ObjectVal(attributes))
+	if err != nil {
+		return err
+	}
+	_, err = r.Diff(context.Background(), &terraform.InstanceState{RawConfig: rawConfig}, terraform.NewResourceConfigRaw(raw), &providerMeta{})
+	return err
+}
+
+func TestCustomizeDiffIpBlockVersion(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]string
+		valid  bool
+	}{
+		{"default version /28", map[string]string{"location": "PHX", "cidr_block_size": "/28"}, true},
+		{"V4 /31", map[string]string{"location": "PHX", "cidr_block_size": "/31", "ip_version": "V4"}, true},
+		{"V4 /27", map[string]string{"location": "PHX", "cidr_block_size": "/27", "ip_version": "V4"}, false},
+		{"V4 /20", map[string]string{"location": "PHX", "cidr_block_size": "/20"}, false},
+		{"V4 /32", map[string]string{"location": "PHX", "cidr_block_size": "/32"}, false},
+		{"V4 /64", map[string]string{"location": "PHX", "cidr_block_size": "/64"}, false},
+		{"V6 /64", map[string]string{"location": "PHX", "cidr_block_size": "/64", "ip_version": "V6"}, true},
+		{"V6 /56", map[string]string{"location": "PHX", "cidr_block_size": "/56", "ip_version": "V6"}, false},
+		{"V6 /48", map[string]string{"location": "PHX", "cidr_block_size": "/48", "ip_version": "V6"}, false},
+		{"V6 /28", map[string]string{"location": "PHX", "cidr_block_size": "/28", "ip_version": "V6"}, false},
+		{"invalid size", map[string]string{"location": "PHX", "cidr_block_size": "28"}, false},
+		{"V4 cidr", map[string]string{"location": "PHX", "cidr": "203.0.113.0/24", "ip_version": "V4"}, true},
+		{"V6 cidr", map[string]string{"location": "PHX", "cidr": "2001:db8::/48", "ip_version": "V6"}, true},
+		{"V6 cidr as V4", map[string]string{"location": "PHX", "cidr": "2001:db8::/48", "ip_version": "V4"}, false},
+		{"V4 cidr as V6", map[string]string{"location": "PHX", "cidr": "203.0.113.0/24", "ip_version": "V6"}, false},
+		{"cidr without version", map[string]string{"location": "PHX", "cidr": "2001:db8::/48"}, true},
+		{"invalid cidr", map[string]string{"location": "PHX", "cidr": "203.0.113.0", "ip_version": "V4"}, false},
+	}
+	for _, c := range cases {
+		err := diffIpBlock(c.config)
+		if valid := err == nil; valid != c.valid {
+			t.Errorf("%s: customizeDiffIpBlockVersion() returned error %v, expected valid %t", c.name, err, c.valid)
+		}
+	}
+}
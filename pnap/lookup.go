@@ -0,0 +1,113 @@
+package pnap
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// lookupItem holds the attributes of a listed object that singular data sources match on.
+type lookupItem struct {
+	id        string
+	name      string
+	createdOn time.Time
+}
+
+// lookupFilter holds the lookup arguments of a singular data source.
+type lookupFilter struct {
+	kind       string
+	nameAttr   string
+	id         string
+	name       string
+	nameRegex  *regexp.Regexp
+	mostRecent bool
+	criteria   []string
+	arguments  []string
+}
+
+// newLookupFilter reads the id, name, name regex and most_recent arguments of a singular data source.
+// The name argument is called nameAttr and its regex counterpart nameAttr_regex; kind names the object in errors.
+func newLookupFilter(d *schema.ResourceData, kind string, nameAttr string) (*lookupFilter, error) {
+	filter := &lookupFilter{kind: kind, nameAttr: nameAttr, arguments: []string{"id"}}
+	if v, ok := d.GetOk("id"); ok {
+		filter.id = v.(string)
+		filter.criteria = append(filter.criteria, fmt.Sprintf("id %q", filter.id))
+	}
+	if len(nameAttr) > 0 {
+		filter.arguments = append(filter.arguments, nameAttr, nameAttr+"_regex")
+		if v, ok := d.GetOk(nameAttr); ok {
+			filter.name = v.(string)
+			filter.criteria = append(filter.criteria, fmt.Sprintf("%s %q", nameAttr, filter.name))
+		}
+		if v, ok := d.GetOk(nameAttr + "_regex"); ok {
+			nameRegex, err := regexp.Compile(v.(string))
+			if err != nil {
+				return nil, err
+			}
+			filter.nameRegex = nameRegex
+			filter.criteria = append(filter.criteria, fmt.Sprintf("%s_regex %q", nameAttr, v.(string)))
+		}
+	}
+	if v, ok := d.GetOk("most_recent"); ok {
+		filter.mostRecent = v.(bool)
+	}
+	return filter, nil
+}
+
+// addCriteria records an additional criteria applied by the caller, such as a query parameter, for error messages.
+func (f *lookupFilter) addCriteria(attr string, value string) {
+	f.arguments = append(f.arguments, attr)
+	if len(value) > 0 {
+		f.criteria = append(f.criteria, fmt.Sprintf("%s %q", attr, value))
+	}
+}
+
+func (f *lookupFilter) matches(item lookupItem) bool {
+	if len(f.id) > 0 && item.id != f.id {
+		return false
+	}
+	if len(f.name) > 0 && item.name != f.name {
+		return false
+	}
+	if f.nameRegex != nil && !f.nameRegex.MatchString(item.name) {
+		return false
+	}
+	return true
+}
+
+func (f *lookupFilter) describe() string {
+	return strings.Join(f.criteria, ", ")
+}
+
+// lookupOne returns the single item matching the filter. It fails if the filter has no criteria, if nothing
+// matches, or if more than one item matches and most_recent is not set, in which case the most recently created
+// item is returned.
+func lookupOne[T any](f *lookupFilter, items []T, describe func(T) lookupItem) (T, error) {
+	var found T
+	var foundItem lookupItem
+	if len(f.criteria) == 0 {
+		return found, fmt.Errorf("one of %s must be set to look up a %s", strings.Join(f.arguments, ", "), f.kind)
+	}
+	numOfMatches := 0
+	for _, instance := range items {
+		item := describe(instance)
+		if !f.matches(item) {
+			continue
+		}
+		numOfMatches++
+		if numOfMatches == 1 || item.createdOn.After(foundItem.createdOn) {
+			found = instance
+			foundItem = item
+		}
+	}
+	if numOfMatches == 0 {
+		return found, fmt.Errorf("no %s found matching %s", f.kind, f.describe())
+	}
+	if numOfMatches > 1 && !f.mostRecent {
+		return found, fmt.Errorf("too many %ss found matching %s (found %d, expected 1)", f.kind, f.describe(), numOfMatches)
+	}
+	return found, nil
+}
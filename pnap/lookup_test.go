@@ -0,0 +1,42 @@
+package pnap
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLookupOne(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := []lookupItem{
+		{id: "1", name: "web-1", createdOn: created},
+		{id: "2", name: "web-2", createdOn: created.Add(time.Hour)},
+		{id: "3", name: "db-1", createdOn: created.Add(2 * time.Hour)},
+	}
+	cases := []struct {
+		name     string
+		filter   *lookupFilter
+		expected string
+	}{
+		{"no criteria", &lookupFilter{kind: "server", arguments: []string{"id", "hostname", "hostname_regex"}}, ""},
+		{"no criteria with most_recent", &lookupFilter{kind: "server", mostRecent: true}, ""},
+		{"id", &lookupFilter{kind: "server", id: "2", criteria: []string{`id "2"`}}, "2"},
+		{"name", &lookupFilter{kind: "server", name: "db-1", criteria: []string{`hostname "db-1"`}}, "3"},
+		{"no match", &lookupFilter{kind: "server", name: "web-3", criteria: []string{`hostname "web-3"`}}, ""},
+		{"regex matching several", &lookupFilter{kind: "server", nameRegex: regexp.MustCompile("^web-"), criteria: []string{`hostname_regex "^web-"`}}, ""},
+		{"regex most recent", &lookupFilter{kind: "server", nameRegex: regexp.MustCompile("^web-"), mostRecent: true, criteria: []string{`hostname_regex "^web-"`}}, "2"},
+		{"added criteria only", &lookupFilter{kind: "server", mostRecent: true, criteria: []string{`location "PHX"`}}, "3"},
+	}
+	for _, c := range cases {
+		found, err := lookupOne(c.filter, items, func(item lookupItem) lookupItem { return item })
+		if len(c.expected) == 0 {
+			if err == nil {
+				t.Errorf("%s: lookupOne() = %s, expected an error", c.name, found.id)
+			}
+			continue
+		}
+		if err != nil || found.id != c.expected {
+			t.Errorf("%s: lookupOne() = %s, %v, expected %s", c.name, found.id, err, c.expected)
+		}
+	}
+}
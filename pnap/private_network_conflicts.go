@@ -0,0 +1,127 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/privatenetwork"
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkstorageapi/storagenetwork"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// privateNetworkRanges holds the private address ranges of RFC 1918.
+var privateNetworkRanges = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+}
+
+// isPrivateNetworkRange checks whether the prefix lies within one of the RFC 1918 ranges.
+func isPrivateNetworkRange(prefix netip.Prefix) bool {
+	for _, r := range privateNetworkRanges {
+		if r.Bits() <= prefix.Bits() && r.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+// customizeDiffPrivateNetworkConflicts checks during plan that the CIDR of a new private network is an RFC 1918
+// range that overlaps no other private or storage network in the location and that its VLAN is not used by another
+// network in the location, and that no other private network is the default of the location when location_default
+// is set.
+func customizeDiffPrivateNetworkConflicts(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("location") {
+		return nil
+	}
+	isNew := len(d.Id()) == 0
+	location := d.Get("location").(string)
+
+	var prefix *netip.Prefix
+	if v := d.GetRawConfig().GetAttr("cidr"); isNew && v.IsKnown() && !v.IsNull() && len(v.AsString()) > 0 {
+		p, err := netip.ParsePrefix(v.AsString())
+		if err != nil {
+			return fmt.Errorf("invalid cidr %q: %v", v.AsString(), err)
+		}
+		if !p.Addr().Is4() || !isPrivateNetworkRange(p) {
+			return fmt.Errorf("cidr %s is not a private IPv4 range of RFC 1918 (10.0.0.0/8, 172.16.0.0/12 or 192.168.0.0/16)", p)
+		}
+		p = p.Masked()
+		prefix = &p
+	}
+	vlanID := -1
+	if isNew && d.NewValueKnown("vlan_id") {
+		if v := d.GetRawConfig().GetAttr("vlan_id"); !v.IsNull() {
+			vlanID = d.Get("vlan_id").(int)
+		}
+	}
+	locationDefault := false
+	if v := d.GetRawConfig().GetAttr("location_default"); (isNew || d.HasChange("location_default")) && v.IsKnown() && !v.IsNull() {
+		locationDefault = v.True()
+	}
+	if prefix == nil && vlanID < 0 && !locationDefault {
+		return nil
+	}
+
+	client := m.(*providerMeta).client
+	privateNetworksCommand := privatenetwork.NewGetPrivateNetworksCommand(client)
+	privateNetworks, err := privateNetworksCommand.Execute()
+	if err != nil {
+		return err
+	}
+	for _, j := range privateNetworks {
+		if j.Location != location || j.Id == d.Id() {
+			continue
+		}
+		if prefix != nil && j.Cidr != nil {
+			if other, err := netip.ParsePrefix(*j.Cidr); err == nil && prefix.Overlaps(other) {
+				return fmt.Errorf("cidr %s overlaps cidr %s of private network %s (%s) in location %s", prefix, other, j.Name, j.Id, location)
+			}
+		}
+		if vlanID >= 0 && int(j.VlanId) == vlanID {
+			return fmt.Errorf("vlan_id %d is used by private network %s (%s) in location %s", vlanID, j.Name, j.Id, location)
+		}
+		if locationDefault && j.LocationDefault {
+			return fmt.Errorf("private network %s (%s) is the default private network of location %s; set its location_default "+
+				"to false and apply that first, since only one private network per location can be the default", j.Name, j.Id, location)
+		}
+	}
+
+	if vlanID >= 0 {
+		publicNetworksCommand := publicnetwork.NewGetPublicNetworksCommand(client)
+		publicNetworks, err := publicNetworksCommand.Execute()
+		if err != nil {
+			return err
+		}
+		for _, j := range publicNetworks {
+			if j.Location == location && int(j.VlanId) == vlanID {
+				return fmt.Errorf("vlan_id %d is used by public network %s (%s) in location %s", vlanID, j.Name, j.Id, location)
+			}
+		}
+	}
+
+	if prefix != nil {
+		storageNetworksCommand := storagenetwork.NewGetStorageNetworksCommand(client)
+		storageNetworks, err := storageNetworksCommand.Execute()
+		if err != nil {
+			return err
+		}
+		for _, j := range storageNetworks {
+			if j.Location == nil || *j.Location != location {
+				continue
+			}
+			for _, ip := range j.Ips {
+				if addr, err := netip.ParseAddr(ip); err == nil && prefix.Contains(addr) {
+					storageNetworkID := ""
+					if j.Id != nil {
+						storageNetworkID = *j.Id
+					}
+					return fmt.Errorf("cidr %s overlaps IP %s of storage network %s in location %s", prefix, ip, storageNetworkID, location)
+				}
+			}
+		}
+	}
+	return nil
+}
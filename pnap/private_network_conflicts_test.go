@@ -0,0 +1,35 @@
+package pnap
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestIsPrivateNetworkRange(t *testing.T) {
+	cases := map[string]bool{
+		"10.0.0.0/8":       true,
+		"10.20.30.0/24":    true,
+		"172.16.0.0/12":    true,
+		"172.31.255.0/24":  true,
+		"192.168.0.0/16":   true,
+		"192.168.10.0/28":  true,
+		"10.0.0.0/7":       false,
+		"172.16.0.0/11":    false,
+		"172.32.0.0/24":    false,
+		"192.168.0.0/15":   false,
+		"192.169.0.0/24":   false,
+		"8.8.8.0/24":       false,
+		"100.64.0.0/10":    false,
+		"fd00::/8":         false,
+		"0.0.0.0/0":        false,
+		"203.0.113.0/24":   false,
+		"172.15.255.0/24":  false,
+		"11.0.0.0/8":       false,
+		"192.167.255.0/24": false,
+	}
+	for cidr, expected := range cases {
+		if actual := isPrivateNetworkRange(netip.MustParsePrefix(cidr)); actual != expected {
+			t.Errorf("isPrivateNetworkRange(%s) = %t, expected %t", cidr, actual, expected)
+		}
+	}
+}
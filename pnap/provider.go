@@ -37,18 +37,68 @@ func Provider() *schema.Provider {
 				Optional: true,
 				Default:  "",
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PNAP_PROFILE", ""),
+			},
+			"access_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("PNAP_ACCESS_TOKEN", ""),
+			},
+			"oidc_token_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PNAP_OIDC_TOKEN_FILE", ""),
+			},
+			"default_tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tag_assignment": {
+							Type:     schema.TypeList,
+							Required: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"value": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"require_availability": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"pnap_ssh_key":         resourceSshKey(),
-			"pnap_server":          resourceServer(),
-			"pnap_private_network": resourcePrivateNetwork(),
-			"pnap_reservation":     resourceReservation(),
-			"pnap_ip_block":        resourceIpBlock(),
-			"pnap_rancher_cluster": resourceRancherCluster(),
-			"pnap_tag":             resourceTag(),
-			"pnap_public_network":  resourcePublicNetwork(),
-			"pnap_storage_network": resourceStorageNetwork(),
-			"pnap_bgp_peer_group":  resourceBgpPeerGroup(),
+			"pnap_ssh_key":                 resourceSshKey(),
+			"pnap_server":                  resourceServer(),
+			"pnap_private_network":         resourcePrivateNetwork(),
+			"pnap_reservation":             resourceReservation(),
+			"pnap_ip_block":                resourceIpBlock(),
+			"pnap_rancher_cluster":         resourceRancherCluster(),
+			"pnap_tag":                     resourceTag(),
+			"pnap_public_network":          resourcePublicNetwork(),
+			"pnap_storage_network":         resourceStorageNetwork(),
+			"pnap_bgp_peer_group":          resourceBgpPeerGroup(),
+			"pnap_tag_assignment":          resourceTagAssignment(),
+			"pnap_public_network_ip_block": resourcePublicNetworkIpBlock(),
+			"pnap_server_private_network":  resourceServerPrivateNetwork(),
+			"pnap_server_public_network":   resourceServerPublicNetwork(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"pnap_ssh_key":              dataSourceSshKey(),
@@ -68,63 +118,110 @@ func Provider() *schema.Provider {
 			"pnap_invoices":             dataSourceInvoices(),
 			"pnap_transactions":         dataSourceTransactions(),
 			"pnap_bgp_peer_group":       dataSourceBgpPeerGroup(),
+			"pnap_tags":                 dataSourceTags(),
+			"pnap_servers":              dataSourceServers(),
+			"pnap_server_type":          dataSourceServerType(),
+			"pnap_bgp_sessions":         dataSourceBgpSessions(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
 }
 
+// providerMeta is passed to every resource and data source as the provider meta value.
+type providerMeta struct {
+	client              receiver.BMCSDK
+	defaultTags         []tagAssignment
+	locks               *mutexKV
+	requireAvailability bool
+	plannedServers      *serverDemand
+	allocatedIps        *ipAllocations
+	adoptedIpBlocks     *ipBlockClaims
+}
+
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	client, err := configureClient(d)
+	if err != nil {
+		return nil, err
+	}
+	meta := &providerMeta{
+		client:              client,
+		defaultTags:         expandTagAssignments(d.Get("default_tags").([]interface{})),
+		locks:               newMutexKV(),
+		requireAvailability: d.Get("require_availability").(bool),
+		plannedServers:      newServerDemand(),
+		allocatedIps:        newIpAllocations(),
+		adoptedIpBlocks:     newIpBlockClaims(),
+	}
+	return meta, nil
+}
+
+func configureClient(d *schema.ResourceData) (receiver.BMCSDK, error) {
 	clientId := d.Get("client_id").(string)
 	clientSecret := d.Get("client_secret").(string)
 	configFilePath := d.Get("config_file_path").(string)
 	tokenUrl := d.Get("token_url").(string)
 	apiBaseUrl := d.Get("api_base_url").(string)
+	profile := d.Get("profile").(string)
+	accessToken := d.Get("access_token").(string)
+	oidcTokenFile := d.Get("oidc_token_file").(string)
 
 	configuration := dto.Configuration{}
 	configuration.UserAgent = "terraform-provider-pnap/0.33.0"
 	configuration.PoweredBy = "terraform-provider-pnap/0.33.0"
-	if (clientId != "") && (clientSecret != "") {
+
+	if len(tokenUrl) == 0 {
+		tokenUrl = pnapDefaultTokenUrl
+	}
+	if len(apiBaseUrl) == 0 {
+		apiBaseUrl = pnapDefaultApiBaseUrl
+	}
+
+	switch providerAuthMethod(accessToken, oidcTokenFile, clientId, clientSecret, profile, configFilePath) {
+	case authMethodAccessToken:
+		configuration.BearerToken = accessToken
+		configuration.ApiHostName = apiBaseUrl
+		cl := receiver.NewBMCSDK(configuration)
+		return cl, nil
+	case authMethodOidcTokenExchange:
+		source := newOidcTokenSource(oidcTokenFile, tokenUrl, clientId)
+		token, err := source.Token()
+		if err != nil {
+			return receiver.BMCSDK{}, err
+		}
+		refreshBearerToken(token.AccessToken, source)
+		configuration.BearerToken = token.AccessToken
+		configuration.ApiHostName = apiBaseUrl
+		cl := receiver.NewBMCSDK(configuration)
+		return cl, nil
+	case authMethodClientCredentials:
 		configuration.ClientID = clientId
 		configuration.ClientSecret = clientSecret
-		if (tokenUrl != "") && (apiBaseUrl != "") {
-			configuration.TokenURL = tokenUrl
-			configuration.ApiHostName = apiBaseUrl
-		} else {
-			configuration.TokenURL = "https://auth.phoenixnap.com/auth/realms/BMC/protocol/openid-connect/token"
-			configuration.ApiHostName = "https://api.phoenixnap.com/"
+		configuration.TokenURL = tokenUrl
+		configuration.ApiHostName = apiBaseUrl
+		cl := receiver.NewBMCSDK(configuration)
+		return cl, nil
+	case authMethodProfile:
+		credentials, err := readConfigFileProfile(configFilePath, profile)
+		if err != nil {
+			return receiver.BMCSDK{}, err
+		}
+		configuration.ClientID = credentials.ClientId
+		configuration.ClientSecret = credentials.ClientSecret
+		configuration.TokenURL = tokenUrl
+		if len(credentials.TokenUrl) > 0 {
+			configuration.TokenURL = credentials.TokenUrl
+		}
+		configuration.ApiHostName = apiBaseUrl
+		if len(credentials.ApiHostName) > 0 {
+			configuration.ApiHostName = credentials.ApiHostName
 		}
-		/* auth := dto.Authentication{ClientID : clientId,
-		ClientSecret: clientSecret,
-		TokenURL: "https://auth.phoenixnap.com/auth/realms/BMC/protocol/openid-connect/token",
-		ApiHostName:"https://api.phoenixnap.com/",
-		PoweredBy: "terraform-provider-pnap"}
-		cl := newClient.NewPNAPClient(auth) */
 		cl := receiver.NewBMCSDK(configuration)
 		return cl, nil
-	}
-
-	if configFilePath != "" {
-
+	case authMethodConfigFile:
 		cl, confErr := receiver.NewBMCSDKWithCustomConfig(configFilePath, configuration)
-		/* if confErr == nil {
-			auth := dto.Authentication{ClientID : "",
-			ClientSecret: "",
-			TokenURL: "",
-			ApiHostName:"",
-			PoweredBy: "terraform-provider-pnap"}
-			cl.SetAuthentication(auth)
-		} */
 		return cl, confErr
 	}
 
 	client, confErr := receiver.NewBMCSDKWithDefaultConfig(configuration)
-	/* if confErr == nil {
-		auth := dto.Authentication{ClientID : "",
-		ClientSecret: "",
-		TokenURL: "",
-		ApiHostName:"",
-		PoweredBy: "terraform-provider-pnap"}
-		client.SetAuthentication(auth)
-	} */
 	return client, confErr
 }
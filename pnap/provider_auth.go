@@ -0,0 +1,232 @@
+package pnap
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	pnapDefaultTokenUrl   = "https://auth.phoenixnap.com/auth/realms/BMC/protocol/openid-connect/token"
+	pnapDefaultApiBaseUrl = "https://api.phoenixnap.com/"
+
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeJwt           = "urn:ietf:params:oauth:token-type:jwt"
+	tokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+const (
+	authMethodAccessToken       = "access_token"
+	authMethodOidcTokenExchange = "oidc_token_file"
+	authMethodClientCredentials = "client_credentials"
+	authMethodProfile           = "profile"
+	authMethodConfigFile        = "config_file_path"
+	authMethodDefaultConfigFile = "default_config_file"
+)
+
+// providerAuthMethod returns how the provider authenticates given its arguments, in order of precedence: an access
+// token, an OIDC token exchange, client credentials, a profile of the configuration file, and finally the credentials
+// of the configuration file at config_file_path or at the default location.
+func providerAuthMethod(accessToken string, oidcTokenFile string, clientId string, clientSecret string, profile string, configFilePath string) string {
+	switch {
+	case accessToken != "":
+		return authMethodAccessToken
+	case oidcTokenFile != "":
+		return authMethodOidcTokenExchange
+	case clientId != "" && clientSecret != "":
+		return authMethodClientCredentials
+	case profile != "":
+		return authMethodProfile
+	case configFilePath != "":
+		return authMethodConfigFile
+	}
+	return authMethodDefaultConfigFile
+}
+
+// configFileProfile holds the credentials of a single named profile in the configuration file.
+type configFileProfile struct {
+	ClientId     string `yaml:"clientId"`
+	ClientSecret string `yaml:"clientSecret"`
+	TokenUrl     string `yaml:"tokenUrl"`
+	ApiHostName  string `yaml:"apiHostName"`
+}
+
+// configFile is the layout of the configuration file when profiles are used.
+type configFile struct {
+	Profiles map[string]configFileProfile `yaml:"profiles"`
+}
+
+// defaultConfigFilePath returns the location of the configuration file used when config_file_path is not set.
+func defaultConfigFilePath() (string, error) {
+	if runtime.GOOS == "windows" {
+		appData, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(appData, "pnap", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".pnap", "config.yaml"), nil
+}
+
+// readConfigFileProfile reads the credentials of the named profile from the configuration file.
+func readConfigFileProfile(path string, profile string) (*configFileProfile, error) {
+	if len(path) == 0 {
+		defaultPath, err := defaultConfigFilePath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading configuration file %s: %v", path, err)
+	}
+	conf := configFile{}
+	if err := yaml.Unmarshal(content, &conf); err != nil {
+		return nil, fmt.Errorf("error parsing configuration file %s: %v", path, err)
+	}
+	credentials, exists := conf.Profiles[profile]
+	if !exists {
+		return nil, fmt.Errorf("profile %s not found in configuration file %s", profile, path)
+	}
+	if len(credentials.ClientId) == 0 || len(credentials.ClientSecret) == 0 {
+		return nil, fmt.Errorf("profile %s in configuration file %s must define clientId and clientSecret", profile, path)
+	}
+	return &credentials, nil
+}
+
+// oidcTokenRefreshMargin is how long before its expiry an exchanged access token is replaced.
+const oidcTokenRefreshMargin = 2 * time.Minute
+
+// oidcTokenSource exchanges the JWT stored in a file for a phoenixNAP access token. The file is read on every
+// exchange, since CI systems and workload identity providers rotate it.
+type oidcTokenSource struct {
+	tokenFile string
+	tokenUrl  string
+	clientId  string
+}
+
+// newOidcTokenSource returns a token source that exchanges the OIDC token again shortly before the access token
+// expires.
+func newOidcTokenSource(tokenFile string, tokenUrl string, clientId string) oauth2.TokenSource {
+	return oauth2.ReuseTokenSourceWithExpiry(nil, &oidcTokenSource{tokenFile: tokenFile, tokenUrl: tokenUrl, clientId: clientId}, oidcTokenRefreshMargin)
+}
+
+// Token exchanges the OIDC token using OAuth 2.0 token exchange.
+func (s *oidcTokenSource) Token() (*oauth2.Token, error) {
+	content, err := os.ReadFile(s.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading OIDC token file %s: %v", s.tokenFile, err)
+	}
+	subjectToken := strings.TrimSpace(string(content))
+	if len(subjectToken) == 0 {
+		return nil, fmt.Errorf("OIDC token file %s is empty", s.tokenFile)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", tokenExchangeGrantType)
+	form.Set("subject_token", subjectToken)
+	form.Set("subject_token_type", tokenTypeJwt)
+	form.Set("requested_token_type", tokenTypeAccessToken)
+	if len(s.clientId) > 0 {
+		form.Set("client_id", s.clientId)
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.PostForm(s.tokenUrl, form)
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging OIDC token at %s: %v", s.tokenUrl, err)
+	}
+	defer resp.Body.Close()
+
+	tokenResponse := struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return nil, fmt.Errorf("error decoding token exchange response (HTTP %d): %v", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token exchange failed (HTTP %d): %s %s", resp.StatusCode, tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+	if len(tokenResponse.AccessToken) == 0 {
+		return nil, fmt.Errorf("token exchange response from %s did not contain an access token", s.tokenUrl)
+	}
+	token := &oauth2.Token{AccessToken: tokenResponse.AccessToken, TokenType: "Bearer"}
+	if tokenResponse.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+		log.Printf("[INFO] Exchanged OIDC token for an access token that expires at %s", token.Expiry.Format(time.RFC3339))
+	}
+	return token, nil
+}
+
+// bearerTokenRefresher replaces the static bearer token that a provider configuration passed to the helper SDK
+// with the current token of its token source. The helper SDK takes the bearer token as a string and sends its
+// requests through http.DefaultTransport, so the token is swapped there. Requests are matched by the initial
+// token, which keeps the tokens of provider configurations with different credentials apart.
+type bearerTokenRefresher struct {
+	base    http.RoundTripper
+	mu      sync.RWMutex
+	sources map[string]oauth2.TokenSource
+}
+
+var (
+	defaultBearerTokenRefresher     *bearerTokenRefresher
+	defaultBearerTokenRefresherOnce sync.Once
+)
+
+// refreshBearerToken makes requests carrying the initial token of the token source use its current token instead.
+func refreshBearerToken(initialToken string, source oauth2.TokenSource) {
+	defaultBearerTokenRefresherOnce.Do(func() {
+		defaultBearerTokenRefresher = &bearerTokenRefresher{base: http.DefaultTransport, sources: make(map[string]oauth2.TokenSource)}
+		http.DefaultTransport = defaultBearerTokenRefresher
+	})
+	defaultBearerTokenRefresher.register(initialToken, source)
+}
+
+func (t *bearerTokenRefresher) register(initialToken string, source oauth2.TokenSource) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sources[initialToken] = source
+}
+
+// RoundTrip sends the request with the current token of the matching token source, if any.
+func (t *bearerTokenRefresher) RoundTrip(req *http.Request) (*http.Response, error) {
+	initialToken, isBearer := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !isBearer {
+		return t.base.RoundTrip(req)
+	}
+	t.mu.RLock()
+	source, exists := t.sources[initialToken]
+	t.mu.RUnlock()
+	if !exists {
+		return t.base.RoundTrip(req)
+	}
+	token, err := source.Token()
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	refreshed := req.Clone(req.Context())
+	token.SetAuthHeader(refreshed)
+	return t.base.RoundTrip(refreshed)
+}
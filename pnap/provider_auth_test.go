@@ -0,0 +1,200 @@
+package pnap
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestProviderAuthMethod(t *testing.T) {
+	cases := []struct {
+		name                                                                        string
+		accessToken, oidcTokenFile, clientId, clientSecret, profile, configFilePath string
+		expected                                                                    string
+	}{
+		{"everything set", "token", "/tmp/jwt", "id", "secret", "default", "/tmp/config.yaml", authMethodAccessToken},
+		{"oidc over client credentials", "", "/tmp/jwt", "id", "secret", "default", "/tmp/config.yaml", authMethodOidcTokenExchange},
+		{"client credentials over profile", "", "", "id", "secret", "default", "/tmp/config.yaml", authMethodClientCredentials},
+		{"client id without secret", "", "", "id", "", "default", "", authMethodProfile},
+		{"profile over config file", "", "", "", "", "default", "/tmp/config.yaml", authMethodProfile},
+		{"config file", "", "", "", "", "", "/tmp/config.yaml", authMethodConfigFile},
+		{"nothing set", "", "", "", "", "", "", authMethodDefaultConfigFile},
+	}
+	for _, c := range cases {
+		if actual := providerAuthMethod(c.accessToken, c.oidcTokenFile, c.clientId, c.clientSecret, c.profile, c.configFilePath); actual != c.expected {
+			t.Errorf("%s: providerAuthMethod() = %s, expected %s", c.name, actual, c.expected)
+		}
+	}
+}
+
+func TestReadConfigFileProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `profiles:
+  default:
+    clientId: default-id
+    clientSecret: default-secret
+  staging:
+    clientId: staging-id
+    clientSecret: staging-secret
+    tokenUrl: https://auth.example.com/token
+    apiHostName: https://api.example.com/
+  incomplete:
+    clientId: incomplete-id
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	credentials, err := readConfigFileProfile(path, "staging")
+	if err != nil {
+		t.Fatalf("readConfigFileProfile(staging) returned error: %v", err)
+	}
+	expected := configFileProfile{ClientId: "staging-id", ClientSecret: "staging-secret", TokenUrl: "https://auth.example.com/token", ApiHostName: "https://api.example.com/"}
+	if *credentials != expected {
+		t.Errorf("readConfigFileProfile(staging) = %+v, expected %+v", *credentials, expected)
+	}
+	credentials, err = readConfigFileProfile(path, "default")
+	if err != nil || credentials.ClientId != "default-id" || len(credentials.TokenUrl) > 0 {
+		t.Errorf("readConfigFileProfile(default) = %+v, %v", credentials, err)
+	}
+
+	for _, profile := range []string{"missing", "incomplete"} {
+		if _, err := readConfigFileProfile(path, profile); err == nil {
+			t.Errorf("readConfigFileProfile(%s) returned no error", profile)
+		}
+	}
+	if _, err := readConfigFileProfile(filepath.Join(t.TempDir(), "missing.yaml"), "default"); err == nil {
+		t.Errorf("readConfigFileProfile() of a missing file returned no error")
+	}
+	invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("profiles: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readConfigFileProfile(invalid, "default"); err == nil {
+		t.Errorf("readConfigFileProfile() of an invalid file returned no error")
+	}
+}
+
+// newTokenExchangeServer returns a token endpoint that issues a new access token on every exchange.
+func newTokenExchangeServer(t *testing.T, expiresIn int64, exchanges *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parsing token exchange request: %v", err)
+		}
+		if r.Form.Get("grant_type") != tokenExchangeGrantType || r.Form.Get("subject_token_type") != tokenTypeJwt ||
+			r.Form.Get("requested_token_type") != tokenTypeAccessToken || r.Form.Get("client_id") != "client" {
+			t.Errorf("unexpected token exchange request: %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("subject_token") != "jwt" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": "invalid_grant", "error_description": "invalid subject token"})
+			return
+		}
+		n := atomic.AddInt32(exchanges, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-" + string(rune('0'+n)), "expires_in": expiresIn})
+	}))
+}
+
+func TestOidcTokenSource_exchange(t *testing.T) {
+	var exchanges int32
+	server := newTokenExchangeServer(t, 3600, &exchanges)
+	defer server.Close()
+	tokenFile := filepath.Join(t.TempDir(), "jwt")
+	if err := os.WriteFile(tokenFile, []byte("jwt\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := newOidcTokenSource(tokenFile, server.URL, "client")
+	token, err := source.Token()
+	if err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if token.AccessToken != "access-1" || token.Expiry.Before(time.Now().Add(59*time.Minute)) {
+		t.Errorf("Token() = %s expiring at %s, expected access-1 expiring in an hour", token.AccessToken, token.Expiry)
+	}
+	// The token is reused while it is valid.
+	if token, _ = source.Token(); token.AccessToken != "access-1" || atomic.LoadInt32(&exchanges) != 1 {
+		t.Errorf("Token() = %s after %d exchanges, expected access-1 after 1 exchange", token.AccessToken, exchanges)
+	}
+
+	if err := os.WriteFile(tokenFile, []byte("expired"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newOidcTokenSource(tokenFile, server.URL, "client").Token(); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("Token() with a rejected JWT returned error %v, expected invalid_grant", err)
+	}
+	if err := os.WriteFile(tokenFile, []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newOidcTokenSource(tokenFile, server.URL, "client").Token(); err == nil {
+		t.Errorf("Token() with an empty token file returned no error")
+	}
+}
+
+func TestOidcTokenSource_refreshesBeforeExpiry(t *testing.T) {
+	var exchanges int32
+	// The tokens expire within the refresh margin, so every call exchanges again.
+	server := newTokenExchangeServer(t, int64(oidcTokenRefreshMargin/time.Second)-1, &exchanges)
+	defer server.Close()
+	tokenFile := filepath.Join(t.TempDir(), "jwt")
+	if err := os.WriteFile(tokenFile, []byte("jwt"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := newOidcTokenSource(tokenFile, server.URL, "client")
+	first, err := source.Token()
+	if err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	second, err := source.Token()
+	if err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if first.AccessToken == second.AccessToken || atomic.LoadInt32(&exchanges) != 2 {
+		t.Errorf("expected the token to be exchanged again before expiry, got %s and %s after %d exchanges", first.AccessToken, second.AccessToken, exchanges)
+	}
+}
+
+func TestBearerTokenRefresher(t *testing.T) {
+	var authorization string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer api.Close()
+
+	refresher := &bearerTokenRefresher{base: http.DefaultTransport, sources: make(map[string]oauth2.TokenSource)}
+	refresher.register("initial", oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "current"}))
+	client := &http.Client{Transport: refresher}
+
+	cases := []struct {
+		header   string
+		expected string
+	}{
+		{"Bearer initial", "Bearer current"},
+		{"Bearer other", "Bearer other"},
+		{"", ""},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(http.MethodGet, api.URL, nil)
+		if len(c.header) > 0 {
+			req.Header.Set("Authorization", c.header)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request with Authorization %q failed: %v", c.header, err)
+		}
+		resp.Body.Close()
+		if authorization != c.expected {
+			t.Errorf("request with Authorization %q was sent with %q, expected %q", c.header, authorization, c.expected)
+		}
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"fmt"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/bgppeergroup"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
@@ -17,6 +17,8 @@ func resourceBgpPeerGroup() *schema.Resource {
 		Update: resourceBgpPeerGroupUpdate,
 		Delete: resourceBgpPeerGroupDelete,
 
+		CustomizeDiff: customizeDiffBgpPeerGroup,
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
 			Update: schema.DefaultTimeout(pnapRetryTimeout),
@@ -30,8 +32,9 @@ func resourceBgpPeerGroup() *schema.Resource {
 				Required: true,
 			},
 			"asn": {
-				Type:     schema.TypeInt,
-				Required: true,
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateBgpAsn,
 			},
 			"password": {
 				Type:      schema.TypeString,
@@ -42,6 +45,10 @@ func resourceBgpPeerGroup() *schema.Resource {
 			"advertised_routes": {
 				Type:     schema.TypeString,
 				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					bgpAdvertisedRoutesDefault,
+					bgpAdvertisedRoutesNone,
+				}, false),
 			},
 			"status": {
 				Type:     schema.TypeString,
@@ -187,7 +194,7 @@ func resourceBgpPeerGroup() *schema.Resource {
 
 func resourceBgpPeerGroupCreate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &networkapiclient.BgpPeerGroupCreate{}
 	request.Location = d.Get("location").(string)
@@ -215,7 +222,7 @@ func resourceBgpPeerGroupCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceBgpPeerGroupRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	bgpID := d.Id()
 	requestCommand := bgppeergroup.NewGetBgpPeerGroupCommand(client, bgpID)
 	resp, err := requestCommand.Execute()
@@ -275,7 +282,7 @@ func resourceBgpPeerGroupRead(d *schema.ResourceData, m interface{}) error {
 
 func resourceBgpPeerGroupUpdate(d *schema.ResourceData, m interface{}) error {
 	if d.HasChange("asn") || d.HasChange("password") || d.HasChange("advertised_routes") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		request := &networkapiclient.BgpPeerGroupPatch{}
 
 		if d.HasChange("asn") {
@@ -307,7 +314,7 @@ func resourceBgpPeerGroupUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceBgpPeerGroupDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	bgpID := d.Id()
 
@@ -3,12 +3,15 @@ package pnap
 import (
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
 	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	ipapiclient "github.com/phoenixnap/go-sdk-bmc/ipapi/v3"
 )
@@ -25,6 +28,11 @@ func resourceIpBlock() *schema.Resource {
 		Update: resourceIpBlockUpdate,
 		Delete: resourceIpBlockDelete,
 
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffTagsAll,
+			customizeDiffIpBlockVersion,
+		),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
 			Update: schema.DefaultTimeout(pnapRetryTimeout),
@@ -37,13 +45,16 @@ func resourceIpBlock() *schema.Resource {
 				Required: true,
 			},
 			"cidr_block_size": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"cidr_block_size", "cidr"},
 			},
 			"ip_version": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{ipVersionV4, ipVersionV6}, false),
 			},
 			"description": {
 				Type:     schema.TypeString,
@@ -88,9 +99,16 @@ func resourceIpBlock() *schema.Resource {
 					},
 				},
 			},
-			"cidr": {
-				Type:     schema.TypeString,
+			"tags_all": {
+				Type:     schema.TypeMap,
 				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"cidr": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IsCIDR,
 			},
 			"status": {
 				Type:     schema.TypeString,
@@ -120,6 +138,37 @@ func resourceIpBlock() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"network_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"gateway_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"broadcast_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"first_usable_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"usable_ips": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"used_ips": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"available_ips": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -128,8 +177,11 @@ func resourceIpBlock() *schema.Resource {
 }
 
 func resourceIpBlockCreate(d *schema.ResourceData, m interface{}) error {
+	if _, ok := d.GetOk("cidr"); ok {
+		return resourceIpBlockBringYourOwn(d, m)
+	}
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &ipapiclient.IpBlockCreate{}
 	request.Location = d.Get("location").(string)
@@ -142,24 +194,9 @@ func resourceIpBlockCreate(d *schema.ResourceData, m interface{}) error {
 	if len(desc) > 0 {
 		request.Description = &desc
 	}
-	tags := d.Get("tags").([]interface{})
+	tags := mergeDefaultTags(m.(*providerMeta).defaultTags, expandTagAssignments(d.Get("tags").([]interface{})))
 	if len(tags) > 0 {
-		tagsObject := make([]ipapiclient.TagAssignmentRequest, len(tags))
-		for i, j := range tags {
-			tarObject := ipapiclient.TagAssignmentRequest{}
-			tagsItem := j.(map[string]interface{})
-
-			tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
-			tagAssignItem := tagAssign.(map[string]interface{})
-
-			tarObject.Name = tagAssignItem["name"].(string)
-			value := tagAssignItem["value"].(string)
-			if len(value) > 0 {
-				tarObject.Value = &value
-			}
-			tagsObject[i] = tarObject
-		}
-		request.Tags = tagsObject
+		request.Tags = expandIpBlockTags(tags)
 	}
 
 	requestCommand := ipblock.NewCreateIpBlockCommand(client, *request)
@@ -177,8 +214,51 @@ func resourceIpBlockCreate(d *schema.ResourceData, m interface{}) error {
 	return resourceIpBlockRead(d, m)
 }
 
+// resourceIpBlockBringYourOwn adopts a bring your own IP Block with the configured CIDR. The IP API cannot
+// create such blocks, they are onboarded by phoenixNAP once the LOA and ASN are verified, so the provider waits
+// until the block is available in the account. A block that is already in use is not adopted, it can be imported.
+func resourceIpBlockBringYourOwn(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	cidr := d.Get("cidr").(string)
+
+	location := d.Get("location").(string)
+
+	ipBlockID, err := ipBlockWaitForBringYourOwn(cidr, location, &client, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+	if !m.(*providerMeta).adoptedIpBlocks.claim(ipBlockID) {
+		return fmt.Errorf("bring your own ip block %s (%s) is already managed by another resource", ipBlockID, cidr)
+	}
+	d.SetId(ipBlockID)
+
+	if desc, ok := d.GetOk("description"); ok {
+		request := &ipapiclient.IpBlockPatch{}
+		description := desc.(string)
+		request.Description = &description
+		requestCommand := ipblock.NewPatchIpBlockCommand(client, ipBlockID, *request)
+		if _, err := requestCommand.Execute(); err != nil {
+			return err
+		}
+	}
+	tags := mergeDefaultTags(m.(*providerMeta).defaultTags, expandTagAssignments(d.Get("tags").([]interface{})))
+	if len(tags) > 0 {
+		err := updateResourceTags(m.(*providerMeta), tagAssignmentResourceTypeIpBlock, ipBlockID, func(currentTags []tagAssignment) []tagAssignment {
+			for _, tag := range tags {
+				currentTags = setTag(currentTags, tag)
+			}
+			return currentTags
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceIpBlockRead(d, m)
+}
+
 func resourceIpBlockRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	ipBlockID := d.Id()
 	requestCommand := ipblock.NewGetIpBlockCommand(client, ipBlockID)
 	resp, err := requestCommand.Execute()
@@ -235,13 +315,32 @@ func resourceIpBlockRead(d *schema.ResourceData, m interface{}) error {
 	} else {
 		d.Set("description", "")
 	}
-	if len(resp.Tags) > 0 {
-		var tagsInput = d.Get("tags").([]interface{})
-		tags := flattenTags(resp.Tags, tagsInput)
+	var tagsInput = d.Get("tags").([]interface{})
+	configTags := expandTagAssignments(tagsInput)
+	previousTagsAll := d.Get("tags_all").(map[string]interface{})
+	readAll := readsAllTags(d)
+	var resourceTags []ipapiclient.TagAssignment
+	tagsAll := make(map[string]interface{})
+	for _, v := range resp.Tags {
+		if !readAll && !isManagedTag(v.Name, configTags, m.(*providerMeta).defaultTags, previousTagsAll) {
+			continue
+		}
+		if v.Value != nil {
+			tagsAll[v.Name] = *v.Value
+		} else {
+			tagsAll[v.Name] = ""
+		}
+		if isResourceTag(v.Name, configTags, m.(*providerMeta).defaultTags, readAll) {
+			resourceTags = append(resourceTags, v)
+		}
+	}
+	if len(resourceTags) > 0 {
+		tags := flattenTags(resourceTags, tagsInput)
 		if err := d.Set("tags", tags); err != nil {
 			return err
 		}
 	}
+	d.Set("tags_all", tagsAll)
 	if resp.IsSystemManaged != nil {
 		d.Set("is_system_managed", *resp.IsSystemManaged)
 	} else {
@@ -256,12 +355,61 @@ func resourceIpBlockRead(d *schema.ResourceData, m interface{}) error {
 		createdOn := *resp.CreatedOn
 		d.Set("created_on", createdOn.String())
 	}
+	if resp.Cidr != nil {
+		if err := setIpBlockAddresses(d, client, *resp.Cidr, resp.AssignedResourceId, resp.AssignedResourceType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setIpBlockAddresses sets the special, usable, used and available addresses of the IP Block. The usable and
+// available addresses are limited to the first ipBlockMaxListedIps addresses.
+func setIpBlockAddresses(d *schema.ResourceData, client receiver.BMCSDK, cidr string, assignedResourceID *string, assignedResourceType *string) error {
+	addresses, err := computeIpBlockAddresses(cidr)
+	if err != nil {
+		return err
+	}
+	d.Set("network_ip", addresses.network)
+	d.Set("gateway_ip", addresses.gateway)
+	d.Set("broadcast_ip", addresses.broadcast)
+	d.Set("first_usable_ip", addresses.firstUsable)
+	usableIps := addresses.usable
+	if len(usableIps) > ipBlockMaxListedIps {
+		usableIps = usableIps[:ipBlockMaxListedIps]
+	}
+	d.Set("usable_ips", usableIps)
+
+	var usedIps []string
+	if assignedResourceID != nil && assignedResourceType != nil {
+		usedIps, err = getIpBlockUsedIps(client, cidr, *assignedResourceID, *assignedResourceType)
+		if err != nil {
+			return err
+		}
+	}
+	d.Set("used_ips", usedIps)
+
+	used := make(map[string]bool)
+	for _, ip := range usedIps {
+		used[ip] = true
+	}
+	var availableIps []string
+	for _, ip := range addresses.usable {
+		if len(availableIps) == ipBlockMaxListedIps {
+			break
+		}
+		if !used[ip] {
+			availableIps = append(availableIps, ip)
+		}
+	}
+	d.Set("available_ips", availableIps)
 	return nil
 }
 
 func resourceIpBlockUpdate(d *schema.ResourceData, m interface{}) error {
+	tagsChanged := d.HasChange("tags") || d.HasChange("tags_all")
 	if d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		request := &ipapiclient.IpBlockPatch{}
 		var desc = d.Get("description").(string)
 		request.Description = &desc
@@ -272,48 +420,58 @@ func resourceIpBlockUpdate(d *schema.ResourceData, m interface{}) error {
 		if err != nil {
 			return err
 		}
-	} else if d.HasChange("tags") {
-		tags := d.Get("tags").([]interface{})
-		client := m.(receiver.BMCSDK)
-		ipBlockID := d.Id()
-
-		var request []ipapiclient.TagAssignmentRequest
-
-		if len(tags) > 0 {
-			request = make([]ipapiclient.TagAssignmentRequest, len(tags))
-
-			for i, j := range tags {
-				tarObject := ipapiclient.TagAssignmentRequest{}
-				tagsItem := j.(map[string]interface{})
-
-				tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
-				tagAssignItem := tagAssign.(map[string]interface{})
-
-				tarObject.Name = tagAssignItem["name"].(string)
-				value := tagAssignItem["value"].(string)
-				if len(value) > 0 {
-					tarObject.Value = &value
-				}
-				request[i] = tarObject
-			}
-		}
-		requestCommand := ipblock.NewPutTagsIpBlockCommand(client, ipBlockID, request)
-		_, err := requestCommand.Execute()
+	} else if !tagsChanged {
+		return fmt.Errorf("unsupported action")
+	}
+	// Tags are applied together with the other changes.
+	if tagsChanged {
+		err := resourceIpBlockUpdateTags(d, m)
 		if err != nil {
 			return err
 		}
-	} else {
-		return fmt.Errorf("unsupported action")
 	}
 
 	return resourceIpBlockRead(d, m)
 }
 
+// resourceIpBlockUpdateTags assigns the resource tags merged with the provider default tags to the IP Block,
+// keeping the tags that were assigned by other means.
+func resourceIpBlockUpdateTags(d *schema.ResourceData, m interface{}) error {
+	tags := mergeDefaultTags(m.(*providerMeta).defaultTags, expandTagAssignments(d.Get("tags").([]interface{})))
+	client := m.(*providerMeta).client
+	ipBlockID := d.Id()
+	previousTagsAll, _ := d.GetChange("tags_all")
+
+	m.(*providerMeta).locks.Lock(ipBlockID)
+	defer m.(*providerMeta).locks.Unlock(ipBlockID)
+
+	currentTags, err := getIpBlockTags(client, ipBlockID)
+	if err != nil {
+		return err
+	}
+	tags = mergeUnmanagedTags(currentTags, previousTagsAll.(map[string]interface{}), tags)
+
+	var request []ipapiclient.TagAssignmentRequest
+
+	if len(tags) > 0 {
+		request = expandIpBlockTags(tags)
+	}
+	requestCommand := ipblock.NewPutTagsIpBlockCommand(client, ipBlockID, request)
+	_, err = requestCommand.Execute()
+	return err
+}
+
 func resourceIpBlockDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	ipBlockID := d.Id()
 
+	if d.Get("is_bring_your_own").(bool) {
+		// Bring your own IP Blocks are offboarded through phoenixNAP support, so they are only removed from state.
+		log.Printf("[WARN] Bring your own ip block %s is removed from state but not deleted", ipBlockID)
+		return nil
+	}
+
 	waitResultError := ipBlockWaitForUnassign(ipBlockID, &client)
 	if waitResultError != nil {
 		return waitResultError
@@ -328,6 +486,38 @@ func resourceIpBlockDelete(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// getIpBlockTags reads the tags currently assigned to the IP Block.
+func getIpBlockTags(client receiver.BMCSDK, ipBlockID string) ([]tagAssignment, error) {
+	requestCommand := ipblock.NewGetIpBlockCommand(client, ipBlockID)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]tagAssignment, len(resp.Tags))
+	for i, j := range resp.Tags {
+		tags[i].name = j.Name
+		if j.Value != nil {
+			tags[i].value = *j.Value
+		}
+	}
+	return tags, nil
+}
+
+// expandIpBlockTags converts tag assignments to IP Block tag assignment requests.
+func expandIpBlockTags(tags []tagAssignment) []ipapiclient.TagAssignmentRequest {
+	tagsObject := make([]ipapiclient.TagAssignmentRequest, len(tags))
+	for i, j := range tags {
+		tarObject := ipapiclient.TagAssignmentRequest{}
+		tarObject.Name = j.name
+		if len(j.value) > 0 {
+			value := j.value
+			tarObject.Value = &value
+		}
+		tagsObject[i] = tarObject
+	}
+	return tagsObject
+}
+
 func flattenTags(tagsRead []ipapiclient.TagAssignment, tagsInput []interface{}) []interface{} {
 	if len(tagsInput) == 0 {
 		tagsInput = make([]interface{}, 1)
@@ -376,6 +566,85 @@ func ipBlockWaitForUnassign(id string, client *receiver.BMCSDK) error {
 	return nil
 }
 
+func ipBlockWaitForBringYourOwn(cidr string, location string, client *receiver.BMCSDK, timeout time.Duration) (string, error) {
+	log.Printf("Waiting for bring your own ip block %s to be onboarded...", cidr)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"pending verification", "creating", "assigning", "unassigning"},
+		Target:     []string{"unassigned"},
+		Refresh:    refreshForBringYourOwnIpBlock(client, cidr, location),
+		Timeout:    timeout,
+		Delay:      pnapIpBlockRetryDelay,
+		MinTimeout: pnapRetryMinTimeout,
+	}
+
+	result, err := stateConf.WaitForState()
+	if err != nil {
+		return "", fmt.Errorf("error waiting for bring your own ip block (%s) to be onboarded: %v", cidr, err)
+	}
+
+	return result.(string), nil
+}
+
+// refreshForBringYourOwnIpBlock reports the status of the bring your own IP Block with the CIDR,
+// or "pending verification" while it is not yet in the account or has no status. The block must be onboarded
+// in the location and must not be assigned or tagged yet, which would mean it is managed by other means.
+func refreshForBringYourOwnIpBlock(client *receiver.BMCSDK, cidr string, location string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+
+		requestCommand := ipblock.NewGetIpBlocksCommand(*client)
+
+		resp, err := requestCommand.Execute()
+		if err != nil {
+			return "", "", err
+		}
+		for _, instance := range resp {
+			if instance.Cidr == nil || *instance.Cidr != cidr || instance.Id == nil {
+				continue
+			}
+			if instance.IsBringYourOwn == nil || !*instance.IsBringYourOwn {
+				return "", "", fmt.Errorf("ip block %s (%s) is not a bring your own ip block", *instance.Id, cidr)
+			}
+			if instance.Location != nil && *instance.Location != location {
+				return "", "", fmt.Errorf("bring your own ip block %s (%s) is onboarded in location %s, not in location %s", *instance.Id, cidr, *instance.Location, location)
+			}
+			if instance.AssignedResourceId != nil || len(instance.Tags) > 0 {
+				return "", "", fmt.Errorf("bring your own ip block %s (%s) is already in use, import it to manage it with this resource", *instance.Id, cidr)
+			}
+			if instance.Status == nil {
+				return *instance.Id, "pending verification", nil
+			}
+			return *instance.Id, *instance.Status, nil
+		}
+		return "", "pending verification", nil
+	}
+}
+
+// ipBlockClaims records the bring your own IP Blocks adopted by a provider instance, so that two resources
+// with the same CIDR do not both manage the same block.
+type ipBlockClaims struct {
+	lock    sync.Mutex
+	claimed map[string]bool
+}
+
+// newIpBlockClaims returns an empty ipBlockClaims.
+func newIpBlockClaims() *ipBlockClaims {
+	return &ipBlockClaims{
+		claimed: make(map[string]bool),
+	}
+}
+
+// claim claims the IP Block and reports whether it was not claimed before.
+func (c *ipBlockClaims) claim(ipBlockID string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.claimed[ipBlockID] {
+		return false
+	}
+	c.claimed[ipBlockID] = true
+	return true
+}
+
 func refreshForIpBlockStatus(client *receiver.BMCSDK, id string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 
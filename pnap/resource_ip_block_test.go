@@ -10,7 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	helperipblock "github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	ipapiclient "github.com/phoenixnap/go-sdk-bmc/ipapi/v3"
 )
 
@@ -69,7 +68,7 @@ func TestAccPnapIpBlock_basic(t *testing.T) {
 // has been destroyed
 func testAccCheckIpBlockResourceDestroy(s *terraform.State) error {
 	// get configured client from metadata
-	client := testAccProvider.Meta().(receiver.BMCSDK)
+	client := testAccProvider.Meta().(*providerMeta).client
 	// loop through the resources in state, verifying each ip block
 	// is destroyed
 	for _, rs := range s.RootModule().Resources {
@@ -124,7 +123,7 @@ func testAccCheckIpBlockExists(resourceName string, ipBlock *ipapiclient.IpBlock
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(receiver.BMCSDK)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		requestCommand := helperipblock.NewGetIpBlockCommand(client, rs.Primary.ID)
 
@@ -247,7 +246,7 @@ func init() {
 		F: func(region string) error {
 
 			// retrieve the configured client from the test setup
-			client := testAccProvider.Meta().(receiver.BMCSDK)
+			client := testAccProvider.Meta().(*providerMeta).client
 
 			requestCommand := helperipblock.NewGetIpBlocksCommand(client)
 			resp, err := requestCommand.Execute()
@@ -1,12 +1,15 @@
 package pnap
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/privatenetwork"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
@@ -22,10 +25,12 @@ const (
 
 func resourcePrivateNetwork() *schema.Resource {
 	return &schema.Resource{
-		Create: resourcePrivateNetworkCreate,
-		Read:   resourcePrivateNetworkRead,
-		Update: resourcePrivateNetworkUpdate,
-		Delete: resourcePrivateNetworkDelete,
+		Create:        resourcePrivateNetworkCreate,
+		Read:          resourcePrivateNetworkRead,
+		Update:        resourcePrivateNetworkUpdate,
+		DeleteContext: resourcePrivateNetworkDelete,
+
+		CustomizeDiff: customizeDiffPrivateNetworkConflicts,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -62,14 +67,20 @@ func resourcePrivateNetwork() *schema.Resource {
 				Computed: true,
 			},
 			"vlan_id": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(2, 4094),
 			},
 			"force": {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+			"detach_members_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"servers": { // Deprecated
 				Type:     schema.TypeList,
 				Computed: true,
@@ -125,7 +136,7 @@ func resourcePrivateNetwork() *schema.Resource {
 
 func resourcePrivateNetworkCreate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &networkapiclient.PrivateNetworkCreate{}
 	request.Name = d.Get("name").(string)
@@ -165,7 +176,7 @@ func resourcePrivateNetworkCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourcePrivateNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	networkID := d.Id()
 	requestCommand := privatenetwork.NewGetPrivateNetworkCommand(client, networkID)
 	resp, err := requestCommand.Execute()
@@ -201,7 +212,7 @@ func resourcePrivateNetworkRead(d *schema.ResourceData, m interface{}) error {
 
 func resourcePrivateNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 	if d.HasChange("name") || d.HasChange("location_default") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 
 		request := &networkapiclient.PrivateNetworkModify{}
 		request.Name = d.Get("name").(string)
@@ -217,6 +228,8 @@ func resourcePrivateNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 			return err
 		}
 
+	} else if d.HasChange("detach_members_on_destroy") {
+		// Do nothing
 	} else {
 		return fmt.Errorf("unsupported action")
 	}
@@ -224,23 +237,36 @@ func resourcePrivateNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 
 }
 
-func resourcePrivateNetworkDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourcePrivateNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	networkID := d.Id()
 
+	var detached []string
+	if d.Get("detach_members_on_destroy").(bool) {
+		getCommand := privatenetwork.NewGetPrivateNetworkCommand(client, networkID)
+		resp, err := getCommand.Execute()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		detached, err = detachNetworkServers(m.(*providerMeta), serverNetworkTypePrivate, networkID, resp.Memberships)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	waitResultError := privateNetworkWaitForUnassign(networkID, &client)
 	if waitResultError != nil {
-		return waitResultError
+		return diag.FromErr(waitResultError)
 	}
 
 	requestCommand := privatenetwork.NewDeletePrivateNetworkCommand(client, networkID)
 	err := requestCommand.Execute()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return nil
+	return detachedServersWarning(serverNetworkTypePrivate, networkID, detached)
 }
 
 func flattenServers(servers []networkapiclient.PrivateNetworkServer) []interface{} {
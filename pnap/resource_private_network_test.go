@@ -10,7 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	helperprivatenetwork "github.com/PNAP/go-sdk-helper-bmc/command/networkapi/privatenetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
 
@@ -124,7 +123,7 @@ func TestAccPnapPrivateNetwork_force(t *testing.T) {
 // has been destroyed
 func testAccCheckPrivateNetworkResourceDestroy(s *terraform.State) error {
 	// get configured client from metadata
-	client := testAccProvider.Meta().(receiver.BMCSDK)
+	client := testAccProvider.Meta().(*providerMeta).client
 	// loop through the resources in state, verifying each private network
 	// is destroyed
 	for _, rs := range s.RootModule().Resources {
@@ -201,7 +200,7 @@ func testAccCheckPrivateNetworkExists(resourceName string, privateNetwork *netwo
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(receiver.BMCSDK)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		requestCommand := helperprivatenetwork.NewGetPrivateNetworkCommand(client, rs.Primary.ID)
 
@@ -331,7 +330,7 @@ func init() {
 		F: func(region string) error {
 
 			// retrieve the configured client from the test setup
-			client := testAccProvider.Meta().(receiver.BMCSDK)
+			client := testAccProvider.Meta().(*providerMeta).client
 
 			requestCommand := helperprivatenetwork.NewGetPrivateNetworksCommand(client)
 			resp, err := requestCommand.Execute()
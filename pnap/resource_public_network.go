@@ -1,10 +1,12 @@
 package pnap
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -22,10 +24,12 @@ const (
 
 func resourcePublicNetwork() *schema.Resource {
 	return &schema.Resource{
-		Create: resourcePublicNetworkCreate,
-		Read:   resourcePublicNetworkRead,
-		Update: resourcePublicNetworkUpdate,
-		Delete: resourcePublicNetworkDelete,
+		Create:        resourcePublicNetworkCreate,
+		Read:          resourcePublicNetworkRead,
+		Update:        resourcePublicNetworkUpdate,
+		DeleteContext: resourcePublicNetworkDelete,
+
+		CustomizeDiff: customizeDiffPublicNetworkRa,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -121,6 +125,11 @@ func resourcePublicNetwork() *schema.Resource {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+			"detach_members_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -130,7 +139,7 @@ func resourcePublicNetwork() *schema.Resource {
 
 func resourcePublicNetworkCreate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &networkapiclient.PublicNetworkCreate{}
 	request.Name = d.Get("name").(string)
@@ -177,7 +186,7 @@ func resourcePublicNetworkCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourcePublicNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	networkID := d.Id()
 	requestCommand := publicnetwork.NewGetPublicNetworkCommand(client, networkID)
 	resp, err := requestCommand.Execute()
@@ -221,7 +230,7 @@ func resourcePublicNetworkRead(d *schema.ResourceData, m interface{}) error {
 
 func resourcePublicNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 	if d.HasChange("ip_blocks") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		networkID := d.Id()
 		query := &dto.Query{}
 		var force = d.Get("force").(bool)
@@ -304,7 +313,7 @@ func resourcePublicNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 			}
 		}
 	} else if d.HasChange("name") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		networkID := d.Id()
 		request := &networkapiclient.PublicNetworkModify{}
 		var name = d.Get("name").(string)
@@ -318,7 +327,7 @@ func resourcePublicNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 			return err
 		}
 	} else if d.HasChange("ra_enabled") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		networkID := d.Id()
 		request := &networkapiclient.PublicNetworkModify{}
 		raEnabled := d.Get("ra_enabled").(bool)
@@ -329,7 +338,7 @@ func resourcePublicNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 		if err != nil {
 			return err
 		}
-	} else if d.HasChange("force") {
+	} else if d.HasChange("force") || d.HasChange("detach_members_on_destroy") {
 		// Do nothing
 	} else {
 		return fmt.Errorf("unsupported action")
@@ -337,23 +346,36 @@ func resourcePublicNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 	return resourcePublicNetworkRead(d, m)
 }
 
-func resourcePublicNetworkDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourcePublicNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	networkID := d.Id()
 
+	var detached []string
+	if d.Get("detach_members_on_destroy").(bool) {
+		getCommand := publicnetwork.NewGetPublicNetworkCommand(client, networkID)
+		resp, err := getCommand.Execute()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		detached, err = detachNetworkServers(m.(*providerMeta), serverNetworkTypePublic, networkID, resp.Memberships)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	waitResultError := publicNetworkWaitForUnassign(networkID, &client)
 	if waitResultError != nil {
-		return waitResultError
+		return diag.FromErr(waitResultError)
 	}
 
 	requestCommand := publicnetwork.NewDeletePublicNetworkCommand(client, networkID)
 	err := requestCommand.Execute()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return nil
+	return detachedServersWarning(serverNetworkTypePublic, networkID, detached)
 }
 
 func flattenMemberships(memberships []networkapiclient.NetworkMembership) []interface{} {
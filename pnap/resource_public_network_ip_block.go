@@ -0,0 +1,144 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
+	"github.com/PNAP/go-sdk-helper-bmc/dto"
+
+	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
+)
+
+func resourcePublicNetworkIpBlock() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePublicNetworkIpBlockCreate,
+		Read:   resourcePublicNetworkIpBlockRead,
+		Update: resourcePublicNetworkIpBlockUpdate,
+		Delete: resourcePublicNetworkIpBlockDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(pnapRetryTimeout),
+			Update: schema.DefaultTimeout(pnapRetryTimeout),
+			Delete: schema.DefaultTimeout(pnapDeleteRetryTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"public_network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ip_block_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"force": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"cidr": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"used_ips_count": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePublicNetworkIpBlockImport,
+		},
+	}
+}
+
+func resourcePublicNetworkIpBlockCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	networkID := d.Get("public_network_id").(string)
+	ipBlockID := d.Get("ip_block_id").(string)
+
+	request := &networkapiclient.PublicNetworkIpBlockCreate{}
+	request.Id = ipBlockID
+	requestCommand := publicnetwork.NewAddIpBlock2PublicNetworkCommand(client, networkID, *request)
+	_, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+	d.SetId(networkID + "/" + ipBlockID)
+
+	waitResultError := ipBlockWaitForUnassign(ipBlockID, &client)
+	if waitResultError != nil {
+		return waitResultError
+	}
+
+	return resourcePublicNetworkIpBlockRead(d, m)
+}
+
+func resourcePublicNetworkIpBlockRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	networkID := d.Get("public_network_id").(string)
+	ipBlockID := d.Get("ip_block_id").(string)
+
+	requestCommand := publicnetwork.NewGetPublicNetworkCommand(client, networkID)
+	resp, err := requestCommand.Execute()
+	if isNotFoundError(err) {
+		// The public network was deleted, which detached the IP Block.
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, j := range resp.IpBlocks {
+		if j.Id == ipBlockID {
+			d.Set("cidr", j.Cidr)
+			d.Set("used_ips_count", j.UsedIpsCount)
+			return nil
+		}
+	}
+
+	// The IP Block is no longer attached to the public network.
+	d.SetId("")
+	return nil
+}
+
+func resourcePublicNetworkIpBlockUpdate(d *schema.ResourceData, m interface{}) error {
+	if d.HasChange("force") {
+		// Do nothing
+	} else {
+		return fmt.Errorf("unsupported action")
+	}
+	return resourcePublicNetworkIpBlockRead(d, m)
+}
+
+func resourcePublicNetworkIpBlockDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	networkID := d.Get("public_network_id").(string)
+	ipBlockID := d.Get("ip_block_id").(string)
+	query := &dto.Query{}
+	query.Force = d.Get("force").(bool)
+
+	requestCommand := publicnetwork.NewRemoveIpBlockFromPublicNetworkCommandWithQuery(client, networkID, ipBlockID, query)
+	_, err := requestCommand.Execute()
+	if isNotFoundError(err) {
+		// The public network is gone, or the IP Block is no longer attached to it.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return ipBlockWaitForUnassign(ipBlockID, &client)
+}
+
+func resourcePublicNetworkIpBlockImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected public_network_id/ip_block_id", d.Id())
+	}
+	d.Set("public_network_id", parts[0])
+	d.Set("ip_block_id", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	helperpublicnetwork "github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
 
@@ -68,7 +67,7 @@ func TestAccPnapPublicNetwork_basic(t *testing.T) {
 // has been destroyed
 func testAccCheckPublicNetworkResourceDestroy(s *terraform.State) error {
 	// get configured client from metadata
-	client := testAccProvider.Meta().(receiver.BMCSDK)
+	client := testAccProvider.Meta().(*providerMeta).client
 	// loop through the resources in state, verifying each public network
 	// is destroyed
 	for _, rs := range s.RootModule().Resources {
@@ -121,7 +120,7 @@ func testAccCheckPublicNetworkExists(resourceName string, publicNetwork *network
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(receiver.BMCSDK)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		requestCommand := helperpublicnetwork.NewGetPublicNetworkCommand(client, rs.Primary.ID)
 
@@ -187,7 +186,7 @@ func init() {
 		F: func(region string) error {
 
 			// retrieve the configured client from the test setup
-			client := testAccProvider.Meta().(receiver.BMCSDK)
+			client := testAccProvider.Meta().(*providerMeta).client
 
 			requestCommand := helperpublicnetwork.NewGetPublicNetworksCommand(client)
 			resp, err := requestCommand.Execute()
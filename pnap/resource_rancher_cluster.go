@@ -218,7 +218,7 @@ func resourceRancherCluster() *schema.Resource {
 
 func resourceRancherClusterCreate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &rancherapiclient.Cluster{}
 	var name = d.Get("name").(string)
@@ -392,7 +392,7 @@ func resourceRancherClusterCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	clusterID := d.Id()
 
 	requestCommand := cluster.NewGetClusterCommand(client, clusterID)
@@ -436,7 +436,7 @@ func resourceRancherClusterUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceRancherClusterDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	clusterID := d.Id()
 
 	requestCommand := cluster.NewDeleteClusterCommand(client, clusterID)
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/reservation"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	billingapiclient "github.com/phoenixnap/go-sdk-bmc/billingapi/v4"
 )
@@ -159,7 +158,7 @@ func resourceReservation() *schema.Resource {
 }
 
 func resourceReservationCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	request := &billingapiclient.ReservationRequest{}
 	request.Sku = d.Get("sku").(string)
 	if d.Get("quantity") != nil && len(d.Get("quantity").([]interface{})) > 0 {
@@ -187,7 +186,7 @@ func resourceReservationCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceReservationRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	reservationID := d.Id()
 	requestCommand := reservation.NewGetReservationCommand(client, reservationID)
 	resp, err := requestCommand.Execute()
@@ -240,7 +239,7 @@ func resourceReservationRead(d *schema.ResourceData, m interface{}) error {
 
 func resourceReservationUpdate(d *schema.ResourceData, m interface{}) error {
 	if d.HasChange("sku") || d.HasChange("quantity") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		reservationID := d.Id()
 		request := &billingapiclient.ReservationRequest{}
 		request.Sku = d.Get("sku").(string)
@@ -266,7 +265,7 @@ func resourceReservationUpdate(d *schema.ResourceData, m interface{}) error {
 		}
 		d.SetId(resp.Id)
 	} else if d.HasChange("auto_renew") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		newStatus := d.Get("auto_renew").(bool)
 		if !newStatus {
 			reservationID := d.Id()
@@ -1,6 +1,7 @@
 package pnap
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"log"
@@ -8,8 +9,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
@@ -23,8 +26,29 @@ const (
 	pnapDeleteRetryTimeout = 15 * time.Minute
 	pnapRetryDelay         = 5 * time.Second
 	pnapRetryMinTimeout    = 3 * time.Second
+
+	serverReinstallStrategyInPlace = "in-place"
+	serverReinstallStrategyReplace = "replace"
+
+	serverPowerStateOn  = "on"
+	serverPowerStateOff = "off"
+
+	pnapGracefulShutdownTimeout = 300
 )
 
+// serverUpdateFields lists the arguments that can be changed on an existing server, including the ones that are
+// only kept in state.
+var serverUpdateFields = []string{"os", "cloud_init", "storage_configuration", "install_os_to_ram",
+	"rdp_allowed_ips", "management_access_allowed_ips", "bring_your_own_license", "esxi",
+	"action", "power_state", "reboot_trigger", "pricing_model", "transfer_reservation_to", "ipxe", "hostname", "description",
+	"delete_ip_blocks", "force", "reinstall_strategy", "graceful_shutdown", "graceful_shutdown_timeout",
+	"location_preferences", "require_availability", "tags", "tags_all"}
+
+// serverReinstallFields lists the arguments that can only be applied by installing the OS again.
+// The API offers no endpoints to update the Windows and ESXi OS configuration of a provisioned server.
+var serverReinstallFields = []string{"os", "cloud_init", "storage_configuration", "install_os_to_ram",
+	"rdp_allowed_ips", "management_access_allowed_ips", "bring_your_own_license", "esxi"}
+
 func resourceServer() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceServerCreate,
@@ -32,6 +56,15 @@ func resourceServer() *schema.Resource {
 		Update: resourceServerUpdate,
 		Delete: resourceServerDelete,
 
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffTagsAll,
+			customizeDiffServerReinstall,
+			customizeDiffServerAvailability,
+			customizeDiffServerCloudInit,
+			customizeDiffServerStorage,
+			customizeDiffServerIpAllocation,
+		),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
 			Update: schema.DefaultTimeout(pnapRetryTimeout),
@@ -75,8 +108,16 @@ func resourceServer() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"location": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"location", "location_preferences"},
+			},
+			"location_preferences": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"cpu": {
 				Type:     schema.TypeString,
@@ -103,6 +144,32 @@ func resourceServer() *schema.Resource {
 				Computed: true,
 			},
 			"action": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Deprecated:    "Use power_state to power the server on or off and reboot_trigger to reboot it.",
+				ConflictsWith: []string{"power_state", "reboot_trigger"},
+			},
+			"power_state": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					serverPowerStateOn,
+					serverPowerStateOff,
+				}, false),
+			},
+			"graceful_shutdown": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"graceful_shutdown_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      pnapGracefulShutdownTimeout,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"reboot_trigger": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
@@ -206,8 +273,42 @@ func resourceServer() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"user_data": {
-							Type:     schema.TypeString,
+							Type:          schema.TypeString,
+							Optional:      true,
+							ConflictsWith: []string{"cloud_init.0.content", "cloud_init.0.part"},
+						},
+						"content": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							ConflictsWith: []string{"cloud_init.0.user_data", "cloud_init.0.part"},
+						},
+						"part": {
+							Type:          schema.TypeList,
+							Optional:      true,
+							ConflictsWith: []string{"cloud_init.0.user_data", "cloud_init.0.content"},
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"content_type": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      cloudInitContentTypeConfig,
+										ValidateFunc: validation.StringInSlice(cloudInitPartContentTypes, false),
+									},
+									"filename": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"content": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+								},
+							},
+						},
+						"gzip": {
+							Type:     schema.TypeBool,
 							Optional: true,
+							Default:  false,
 						},
 					},
 				},
@@ -308,6 +409,19 @@ func resourceServer() *schema.Resource {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+			"require_availability": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"reinstall_strategy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  serverReinstallStrategyInPlace,
+				ValidateFunc: validation.StringInSlice([]string{
+					serverReinstallStrategyInPlace,
+					serverReinstallStrategyReplace,
+				}, false),
+			},
 			"transfer_reservation_to": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -351,6 +465,11 @@ func resourceServer() *schema.Resource {
 					},
 				},
 			},
+			"tags_all": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 			"network_configuration": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -496,9 +615,19 @@ func resourceServer() *schema.Resource {
 															},
 															"ips": {
 																Type:     schema.TypeSet,
-																Required: true,
+																Optional: true,
+																Computed: true,
 																Elem:     &schema.Schema{Type: schema.TypeString},
 															},
+															"ip_allocation": {
+																Type:     schema.TypeString,
+																Optional: true,
+																ValidateFunc: validation.StringInSlice([]string{
+																	ipAllocationManual,
+																	ipAllocationAuto,
+																}, false),
+																DiffSuppressFunc: suppressIpAllocationDiff,
+															},
 															"status_description": {
 																Type:     schema.TypeString,
 																Computed: true,
@@ -526,6 +655,7 @@ func resourceServer() *schema.Resource {
 			"storage_configuration": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -538,12 +668,18 @@ func resourceServer() *schema.Resource {
 									"raid": {
 										Type:     schema.TypeString,
 										Optional: true,
-										Default:  "NO_RAID",
+										Default:  serverRaidNone,
+										ValidateFunc: validation.StringInSlice([]string{
+											serverRaidNone,
+											serverRaid0,
+											serverRaid1,
+										}, false),
 									},
 									"size": {
-										Type:     schema.TypeInt,
-										Optional: true,
-										Default:  -1,
+										Type:             schema.TypeInt,
+										Optional:         true,
+										Default:          -1,
+										DiffSuppressFunc: suppressRootPartitionSizeDiff,
 									},
 								},
 							},
@@ -551,6 +687,7 @@ func resourceServer() *schema.Resource {
 					},
 				},
 			},
+			"hardware": serverHardwareSchema(),
 			"gpu_configuration": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -581,7 +718,7 @@ func resourceServer() *schema.Resource {
 
 func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &bmcapiclient.ServerCreate{}
 	request.Hostname = d.Get("hostname").(string)
@@ -592,6 +729,18 @@ func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 	request.Os = d.Get("os").(string)
 	request.Type = d.Get("type").(string)
 	request.Location = d.Get("location").(string)
+	if len(request.Location) == 0 {
+		temp := d.Get("location_preferences").([]interface{})
+		preferences := make([]string, len(temp))
+		for i, v := range temp {
+			preferences[i] = fmt.Sprint(v)
+		}
+		location, err := selectServerLocation(client, request.Type, preferences)
+		if err != nil {
+			return err
+		}
+		request.Location = location
+	}
 	var networkType = d.Get("network_type").(string)
 
 	if len(networkType) > 0 {
@@ -626,6 +775,78 @@ func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 	//todo
 	request.SshKeyIds = keyIds
 
+	osConfiguration, err := expandServerOsConfiguration(d)
+	if err != nil {
+		return err
+	}
+	request.OsConfiguration = osConfiguration
+
+	allocatedIps, err := allocateServerPublicNetworkIps(d, m.(*providerMeta))
+	if err != nil {
+		return err
+	}
+
+	tags := mergeDefaultTags(m.(*providerMeta).defaultTags, expandTagAssignments(d.Get("tags").([]interface{})))
+	if len(tags) > 0 {
+		request.Tags = expandServerTags(tags)
+	}
+
+	query := &dto.Query{}
+	var force = d.Get("force").(bool)
+	query.Force = force
+
+	request.NetworkConfiguration = expandServerNetworkConfiguration(d)
+
+	request.StorageConfiguration = expandServerStorageConfiguration(d)
+
+	requestCommand := server.NewCreateServerCommandWithQuery(client, *request, query)
+
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		// The addresses are not used by a server, so other servers can be given them.
+		m.(*providerMeta).allocatedIps.release(allocatedIps)
+		return err
+	} else {
+
+		d.SetId(resp.Id)
+		d.Set("password", resp.Password)
+		if resp.OsConfiguration != nil {
+			d.Set("root_password", resp.OsConfiguration.RootPassword)
+			d.Set("management_ui_url", resp.OsConfiguration.ManagementUiUrl)
+			netrisController := make([]interface{}, 1)
+			netrisControllerItem := make(map[string]interface{})
+			if resp.OsConfiguration.NetrisController != nil {
+				if resp.OsConfiguration.NetrisController.HostOs != nil {
+					netrisControllerItem["host_os"] = *resp.OsConfiguration.NetrisController.HostOs
+				}
+				if resp.OsConfiguration.NetrisController.NetrisWebConsoleUrl != nil {
+					netrisControllerItem["netris_web_console_url"] = *resp.OsConfiguration.NetrisController.NetrisWebConsoleUrl
+				}
+				if resp.OsConfiguration.NetrisController.NetrisUserPassword != nil {
+					netrisControllerItem["netris_user_password"] = *resp.OsConfiguration.NetrisController.NetrisUserPassword
+				}
+			}
+			netrisController[0] = netrisControllerItem
+			d.Set("netris_controller", netrisController)
+		}
+
+		waitResultError := resourceWaitForCreate(resp.Id, &client)
+		if waitResultError != nil {
+			return waitResultError
+		}
+		if d.Get("power_state").(string) == serverPowerStateOff {
+			err = resourceServerPowerOff(d, client)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceServerRead(d, m)
+}
+
+// expandServerOsConfiguration reads the OS configuration of the server, returning nil if none is set.
+func expandServerOsConfiguration(d *schema.ResourceData) (*bmcapiclient.OsConfiguration, error) {
 	temp2 := d.Get("rdp_allowed_ips").(*schema.Set).List()
 	allowedIps := make([]string, len(temp2))
 	for i, v := range temp2 {
@@ -655,7 +876,11 @@ func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 	if d.Get("cloud_init") != nil && len(d.Get("cloud_init").([]interface{})) > 0 {
 		cloudInit := d.Get("cloud_init").([]interface{})[0]
 		cloudInitItem := cloudInit.(map[string]interface{})
-		userData = cloudInitItem["user_data"].(string)
+		var err error
+		userData, err = buildCloudInitUserData(cloudInitItem)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var bootUrl, staticDhcpAddressV4 string
@@ -734,34 +959,13 @@ func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 			netrisSoftgateObject.ControllerAuthKey = &controllerAuthKey
 			dtoOsConfiguration.NetrisSoftgate = &netrisSoftgateObject
 		}
-		request.OsConfiguration = &dtoOsConfiguration
+		return &dtoOsConfiguration, nil
 	}
+	return nil, nil
+}
 
-	tags := d.Get("tags").([]interface{})
-	if len(tags) > 0 {
-		tagsObject := make([]bmcapiclient.TagAssignmentRequest, len(tags))
-		for i, j := range tags {
-			tarObject := bmcapiclient.TagAssignmentRequest{}
-			tagsItem := j.(map[string]interface{})
-
-			tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
-			tagAssignItem := tagAssign.(map[string]interface{})
-
-			tarObject.Name = tagAssignItem["name"].(string)
-			value := tagAssignItem["value"].(string)
-			if len(value) > 0 {
-				tarObject.Value = &value
-			}
-			tagsObject[i] = tarObject
-		}
-		request.Tags = tagsObject
-	}
-
-	query := &dto.Query{}
-	var force = d.Get("force").(bool)
-	query.Force = force
-
-	// network block
+// expandServerNetworkConfiguration reads the network configuration of the server, returning nil if none is set.
+func expandServerNetworkConfiguration(d *schema.ResourceData) *bmcapiclient.NetworkConfiguration {
 	if d.Get("network_configuration") != nil && len(d.Get("network_configuration").([]interface{})) > 0 {
 
 		networkConfiguration := d.Get("network_configuration").([]interface{})[0]
@@ -914,13 +1118,40 @@ func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 				publicNetworkConfigurationObject.PublicNetworks = serPublicNets
 			}
 		}
-		request.NetworkConfiguration = &networkConfigurationObject
-		// b, _ := json.MarshalIndent(request, "", "  ")
-		// log.Printf("request object is" + string(b))
+		return &networkConfigurationObject
+	}
+	return nil
+}
+
+// flattenServerStorageConfiguration returns the storage configuration of the server. A root partition size of -1
+// in the current configuration is kept, as the API reports the size the partition was given.
+func flattenServerStorageConfiguration(storageConfiguration bmcapiclient.StorageConfiguration, current []interface{}) []interface{} {
+	if storageConfiguration.RootPartition == nil {
+		return nil
+	}
+	rootPartitionItem := make(map[string]interface{})
+	rootPartitionItem["raid"] = serverRaidNone
+	if storageConfiguration.RootPartition.Raid != nil {
+		rootPartitionItem["raid"] = *storageConfiguration.RootPartition.Raid
+	}
+	rootPartitionItem["size"] = -1
+	if storageConfiguration.RootPartition.Size != nil {
+		rootPartitionItem["size"] = int(*storageConfiguration.RootPartition.Size)
+	}
+	if len(current) > 0 && current[0] != nil {
+		currentRootPartition := current[0].(map[string]interface{})["root_partition"].([]interface{})
+		if len(currentRootPartition) > 0 && currentRootPartition[0] != nil && currentRootPartition[0].(map[string]interface{})["size"] == -1 {
+			rootPartitionItem["size"] = -1
+		}
 	}
-	// end of network block
 
-	// storage block
+	storageConfigurationItem := make(map[string]interface{})
+	storageConfigurationItem["root_partition"] = []interface{}{rootPartitionItem}
+	return []interface{}{storageConfigurationItem}
+}
+
+// expandServerStorageConfiguration reads the storage configuration of the server, returning nil if none is set.
+func expandServerStorageConfiguration(d *schema.ResourceData) *bmcapiclient.StorageConfiguration {
 	if d.Get("storage_configuration") != nil && len(d.Get("storage_configuration").([]interface{})) > 0 {
 		storageConfiguration := d.Get("storage_configuration").([]interface{})[0]
 		storageConfigurationItem := storageConfiguration.(map[string]interface{})
@@ -943,50 +1174,13 @@ func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 
 			storageConfigurationObject.RootPartition = &rootPartitionObject
 		}
-		request.StorageConfiguration = &storageConfigurationObject
+		return &storageConfigurationObject
 	}
-	// end of storage block
-
-	requestCommand := server.NewCreateServerCommandWithQuery(client, *request, query)
-
-	resp, err := requestCommand.Execute()
-	if err != nil {
-		return err
-	} else {
-
-		d.SetId(resp.Id)
-		d.Set("password", resp.Password)
-		if resp.OsConfiguration != nil {
-			d.Set("root_password", resp.OsConfiguration.RootPassword)
-			d.Set("management_ui_url", resp.OsConfiguration.ManagementUiUrl)
-			netrisController := make([]interface{}, 1)
-			netrisControllerItem := make(map[string]interface{})
-			if resp.OsConfiguration.NetrisController != nil {
-				if resp.OsConfiguration.NetrisController.HostOs != nil {
-					netrisControllerItem["host_os"] = *resp.OsConfiguration.NetrisController.HostOs
-				}
-				if resp.OsConfiguration.NetrisController.NetrisWebConsoleUrl != nil {
-					netrisControllerItem["netris_web_console_url"] = *resp.OsConfiguration.NetrisController.NetrisWebConsoleUrl
-				}
-				if resp.OsConfiguration.NetrisController.NetrisUserPassword != nil {
-					netrisControllerItem["netris_user_password"] = *resp.OsConfiguration.NetrisController.NetrisUserPassword
-				}
-			}
-			netrisController[0] = netrisControllerItem
-			d.Set("netris_controller", netrisController)
-		}
-
-		waitResultError := resourceWaitForCreate(resp.Id, &client)
-		if waitResultError != nil {
-			return waitResultError
-		}
-	}
-
-	return resourceServerRead(d, m)
+	return nil
 }
 
 func resourceServerRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	serverID := d.Id()
 	requestCommand := server.NewGetServerCommand(client, serverID)
 	resp, err := requestCommand.Execute()
@@ -995,6 +1189,12 @@ func resourceServerRead(d *schema.ResourceData, m interface{}) error {
 	}
 
 	d.Set("status", resp.Status)
+	switch resp.Status {
+	case "powered-on":
+		d.Set("power_state", serverPowerStateOn)
+	case "powered-off":
+		d.Set("power_state", serverPowerStateOff)
+	}
 	d.Set("hostname", resp.Hostname)
 	d.Set("description", resp.Description)
 	d.Set("os", resp.Os)
@@ -1006,6 +1206,7 @@ func resourceServerRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("cpu_frequency_in_ghz", resp.CpuFrequency)
 	d.Set("ram", resp.Ram)
 	d.Set("storage", resp.Storage)
+	d.Set("storage_configuration", flattenServerStorageConfiguration(resp.StorageConfiguration, d.Get("storage_configuration").([]interface{})))
 	d.Set("network_type", resp.NetworkType)
 	d.Set("action", "")
 	var privateIPs []interface{}
@@ -1055,11 +1256,7 @@ func resourceServerRead(d *schema.ResourceData, m interface{}) error {
 			d.Set("esxi", esxi)
 		}
 		if resp.OsConfiguration.CloudInit != nil && resp.OsConfiguration.CloudInit.UserData != nil {
-			cloudInit := make([]interface{}, 1)
-			cloudInitItem := make(map[string]interface{})
-			cloudInitItem["user_data"] = *resp.OsConfiguration.CloudInit.UserData
-			cloudInit[0] = cloudInitItem
-			d.Set("cloud_init", cloudInit)
+			d.Set("cloud_init", flattenCloudInit(*resp.OsConfiguration.CloudInit.UserData, d.Get("cloud_init").([]interface{})))
 		}
 		if resp.OsConfiguration.IPXE != nil {
 			iPXE := make([]interface{}, 1)
@@ -1106,13 +1303,32 @@ func resourceServerRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("provisioned_on", resp.ProvisionedOn.String())
 	}
 
-	if resp.Tags != nil && len(resp.Tags) > 0 {
-		var tagsInput = d.Get("tags").([]interface{})
-		tags := flattenServerTags(resp.Tags, tagsInput)
+	var tagsInput = d.Get("tags").([]interface{})
+	configTags := expandTagAssignments(tagsInput)
+	previousTagsAll := d.Get("tags_all").(map[string]interface{})
+	readAll := readsAllTags(d)
+	var resourceTags []bmcapiclient.TagAssignment
+	tagsAll := make(map[string]interface{})
+	for _, v := range resp.Tags {
+		if !readAll && !isManagedTag(v.Name, configTags, m.(*providerMeta).defaultTags, previousTagsAll) {
+			continue
+		}
+		if v.Value != nil {
+			tagsAll[v.Name] = *v.Value
+		} else {
+			tagsAll[v.Name] = ""
+		}
+		if isResourceTag(v.Name, configTags, m.(*providerMeta).defaultTags, readAll) {
+			resourceTags = append(resourceTags, v)
+		}
+	}
+	if len(resourceTags) > 0 {
+		tags := flattenServerTags(resourceTags, tagsInput)
 		if err := d.Set("tags", tags); err != nil {
 			return err
 		}
 	}
+	d.Set("tags_all", tagsAll)
 
 	var ncInput = d.Get("network_configuration").([]interface{})
 	networkConfiguration := flattenNetworkConfiguration(&resp.NetworkConfiguration, ncInput)
@@ -1127,6 +1343,7 @@ func resourceServerRead(d *schema.ResourceData, m interface{}) error {
 	}
 	gpuConfiguration := flattenGpuConfiguration(gpuConf)
 	d.Set("gpu_configuration", gpuConfiguration)
+	d.Set("hardware", flattenServerHardware(*resp))
 
 	d.Set("superseded_by", resp.SupersededBy)
 	d.Set("supersedes", resp.Supersedes)
@@ -1134,8 +1351,98 @@ func resourceServerRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 func resourceServerUpdate(d *schema.ResourceData, m interface{}) error {
+	if !d.HasChanges(serverUpdateFields...) {
+		return fmt.Errorf("unsupported action")
+	}
+	// Each change is applied on its own, in a fixed order, so that all changes planned together are applied.
+	// The pricing model is changed first, since installing the OS again in place requires a reservation,
+	// and the power state last, once the server is configured. A reinstall sets the tags itself.
+	if (d.HasChange("tags") || d.HasChange("tags_all")) && !d.HasChanges(serverReinstallFields...) {
+		err := resourceServerUpdateTags(d, m)
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChange("pricing_model") {
+		client := m.(*providerMeta).client
+		//var requestCommand command.Executor
+		//reserve action
+		request := &bmcapiclient.ServerReserve{}
+		//request.Id = d.Id()
+		request.PricingModel = d.Get("pricing_model").(string)
+
+		requestCommand := server.NewReserveServerCommand(client, d.Id(), *request)
+		_, err := requestCommand.Execute()
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChanges(serverReinstallFields...) {
+		err := resourceServerReinstall(d, m)
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChange("transfer_reservation_to") {
+		client := m.(*providerMeta).client
+		request := &bmcapiclient.ReservationTransferDetails{}
+		serverID := d.Id()
+		request.TargetServerId = d.Get("transfer_reservation_to").(string)
+
+		requestCommand := server.NewTransferServerReservationCommand(client, serverID, *request)
+		_, err := requestCommand.Execute()
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChange("ipxe") {
+		client := m.(*providerMeta).client
+		serverID := d.Id()
+		request := &bmcapiclient.OsConfigurationIPXE{}
+		nativeVlanConfObject := bmcapiclient.OsConfigurationIPXENativeVlanConfiguration{}
+		if d.Get("ipxe") != nil && len(d.Get("ipxe").([]interface{})) > 0 {
+			iPXE := d.Get("ipxe").([]interface{})[0]
+			iPXEItem := iPXE.(map[string]interface{})
+			if len(iPXEItem["url"].(string)) > 0 {
+				request.Url = iPXEItem["url"].(string)
+			}
+			if iPXEItem["native_vlan_configuration"] != nil && len(iPXEItem["native_vlan_configuration"].([]interface{})) > 0 {
+				nativeVlanConf := iPXEItem["native_vlan_configuration"].([]interface{})[0]
+				nativeVlanConfItem := nativeVlanConf.(map[string]interface{})
+				nativeVlanId := int32(nativeVlanConfItem["vlan_id"].(int))
+				if nativeVlanId > 0 {
+					nativeVlanConfObject.VlanId = &nativeVlanId
+				}
+				staticDhcpAddressV4 := nativeVlanConfItem["static_dhcp_address_v4"].(string)
+				if len(staticDhcpAddressV4) > 0 {
+					nativeVlanConfObject.StaticDhcpAddressV4 = &staticDhcpAddressV4
+				}
+			}
+			request.NativeVlanConfiguration = &nativeVlanConfObject
+		}
+
+		requestCommand := server.NewUpdateServerIPXECommand(client, serverID, *request)
+		_, err := requestCommand.Execute()
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChange("hostname") || d.HasChange("description") {
+		client := m.(*providerMeta).client
+		serverID := d.Id()
+		request := &bmcapiclient.ServerPatch{}
+		var hostname = d.Get("hostname").(string)
+		request.Hostname = &hostname
+		var desc = d.Get("description").(string)
+		request.Description = &desc
+		requestCommand := server.NewPatchServerCommand(client, serverID, *request)
+		_, err := requestCommand.Execute()
+		if err != nil {
+			return err
+		}
+	}
 	if d.HasChange("action") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		//var requestCommand helpercommand.Executor
 		newStatus := d.Get("action").(string)
 
@@ -1168,35 +1475,10 @@ func resourceServerUpdate(d *schema.ResourceData, m interface{}) error {
 			}
 		case "reboot":
 			//reboot
-
-			serverID := d.Id()
-			isIPXE := strings.Contains(d.Get("os").(string), "ipxe")
-			rebootRequest := &bmcapiclient.RebootRequest{}
-			bootType := "STANDARD"
-			if isIPXE {
-				bootType = "IPXE"
-				if d.Get("ipxe") != nil && len(d.Get("ipxe").([]interface{})) > 0 {
-					iPXE := d.Get("ipxe").([]interface{})[0]
-					iPXEItem := iPXE.(map[string]interface{})
-					if len(iPXEItem["url"].(string)) > 0 {
-						url1 := iPXEItem["url"].(string)
-						ipxeUrl := bmcapiclient.NullableString{}
-						ipxeUrl.Set(&url1)
-						rebootRequest.IpxeUrl = ipxeUrl
-					}
-				}
-			}
-			rebootRequest.BootType = &bootType
-
-			requestCommand := server.NewRebootServerCommand(client, serverID, *rebootRequest)
-			_, err := requestCommand.Execute()
+			err := resourceServerReboot(d, client)
 			if err != nil {
 				return err
 			}
-			waitResultError := resourceWaitForCreate(d.Id(), &client)
-			if waitResultError != nil {
-				return waitResultError
-			}
 		case "reset": //Deprecated
 			//reset
 			request := &bmcapiclient.ServerReset{}
@@ -1283,128 +1565,291 @@ func resourceServerUpdate(d *schema.ResourceData, m interface{}) error {
 		case "default":
 			return fmt.Errorf("unsupported action")
 		}
+	}
+	if d.HasChange("power_state") || d.HasChange("reboot_trigger") {
+		client := m.(*providerMeta).client
+		if d.HasChange("power_state") {
+			var err error
+			switch d.Get("power_state").(string) {
+			case serverPowerStateOn:
+				err = resourceServerPowerOn(d, client)
+			case serverPowerStateOff:
+				err = resourceServerPowerOff(d, client)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if d.HasChange("reboot_trigger") && d.Get("power_state").(string) != serverPowerStateOff {
+			err := resourceServerReboot(d, client)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return resourceServerRead(d, m)
+}
 
-	} else if d.HasChange("pricing_model") {
-		client := m.(receiver.BMCSDK)
-		//var requestCommand command.Executor
-		//reserve action
-		request := &bmcapiclient.ServerReserve{}
-		//request.Id = d.Id()
-		request.PricingModel = d.Get("pricing_model").(string)
+// resourceServerUpdateTags assigns the resource tags merged with the provider default tags to the server,
+// keeping the tags that were assigned by other means.
+func resourceServerUpdateTags(d *schema.ResourceData, m interface{}) error {
+	tags := mergeDefaultTags(m.(*providerMeta).defaultTags, expandTagAssignments(d.Get("tags").([]interface{})))
+	client := m.(*providerMeta).client
+	serverID := d.Id()
+	previousTagsAll, _ := d.GetChange("tags_all")
 
-		requestCommand := server.NewReserveServerCommand(client, d.Id(), *request)
-		_, err := requestCommand.Execute()
-		if err != nil {
-			return err
-		}
-	} else if d.HasChange("transfer_reservation_to") {
-		client := m.(receiver.BMCSDK)
-		request := &bmcapiclient.ReservationTransferDetails{}
-		serverID := d.Id()
-		request.TargetServerId = d.Get("transfer_reservation_to").(string)
+	m.(*providerMeta).locks.Lock(serverID)
+	defer m.(*providerMeta).locks.Unlock(serverID)
 
-		requestCommand := server.NewTransferServerReservationCommand(client, serverID, *request)
+	currentTags, err := getServerTags(client, serverID)
+	if err != nil {
+		return err
+	}
+	tags = mergeUnmanagedTags(currentTags, previousTagsAll.(map[string]interface{}), tags)
+
+	var request []bmcapiclient.TagAssignmentRequest
+
+	if len(tags) > 0 {
+		request = expandServerTags(tags)
+	}
+	requestCommand := server.NewSetServerTagsCommand(client, serverID, request)
+	_, err = requestCommand.Execute()
+	return err
+}
+
+func resourceServerDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	serverID := d.Id()
+
+	var deleteIpBlocks = d.Get("delete_ip_blocks").(bool)
+
+	relinquishIpBlock := bmcapiclient.RelinquishIpBlock{}
+	relinquishIpBlock.DeleteIpBlocks = &deleteIpBlocks
+
+	requestCommand := server.NewDeprovisionServerCommand(client, serverID, relinquishIpBlock)
+
+	_, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceServerPowerOn powers on the server unless it is already powered on.
+func resourceServerPowerOn(d *schema.ResourceData, client receiver.BMCSDK) error {
+	serverID := d.Id()
+	if d.Get("status").(string) == "powered-on" {
+		return nil
+	}
+	requestCommand := server.NewPowerOnServerCommand(client, serverID)
+	_, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+	return resourceWaitForPowerON(serverID, &client)
+}
+
+// resourceServerPowerOff powers off the server unless it is already powered off. With graceful_shutdown the
+// OS is shut down first, and the server is powered off only if it does not stop within graceful_shutdown_timeout.
+func resourceServerPowerOff(d *schema.ResourceData, client receiver.BMCSDK) error {
+	serverID := d.Id()
+	if d.Get("status").(string) == "powered-off" {
+		return nil
+	}
+	if d.Get("graceful_shutdown").(bool) {
+		requestCommand := server.NewShutDownServerCommand(client, serverID)
 		_, err := requestCommand.Execute()
 		if err != nil {
 			return err
 		}
-	} else if d.HasChange("ipxe") {
-		client := m.(receiver.BMCSDK)
-		serverID := d.Id()
-		request := &bmcapiclient.OsConfigurationIPXE{}
-		nativeVlanConfObject := bmcapiclient.OsConfigurationIPXENativeVlanConfiguration{}
+		timeout := time.Duration(d.Get("graceful_shutdown_timeout").(int)) * time.Second
+		waitResultError := resourceWaitForPowerOffWithTimeout(serverID, &client, timeout)
+		if waitResultError == nil {
+			return nil
+		}
+		log.Printf("[WARN] Server %s did not shut down gracefully, powering it off: %v", serverID, waitResultError)
+	}
+	requestCommand := server.NewPowerOffServerCommand(client, serverID)
+	_, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+	return resourceWaitForPowerOff(serverID, &client)
+}
+
+// resourceServerReboot reboots the server, booting from the iPXE URL for iPXE servers.
+func resourceServerReboot(d *schema.ResourceData, client receiver.BMCSDK) error {
+	serverID := d.Id()
+	isIPXE := strings.Contains(d.Get("os").(string), "ipxe")
+	rebootRequest := &bmcapiclient.RebootRequest{}
+	bootType := "STANDARD"
+	if isIPXE {
+		bootType = "IPXE"
 		if d.Get("ipxe") != nil && len(d.Get("ipxe").([]interface{})) > 0 {
 			iPXE := d.Get("ipxe").([]interface{})[0]
 			iPXEItem := iPXE.(map[string]interface{})
 			if len(iPXEItem["url"].(string)) > 0 {
-				request.Url = iPXEItem["url"].(string)
-			}
-			if iPXEItem["native_vlan_configuration"] != nil && len(iPXEItem["native_vlan_configuration"].([]interface{})) > 0 {
-				nativeVlanConf := iPXEItem["native_vlan_configuration"].([]interface{})[0]
-				nativeVlanConfItem := nativeVlanConf.(map[string]interface{})
-				nativeVlanId := int32(nativeVlanConfItem["vlan_id"].(int))
-				if nativeVlanId > 0 {
-					nativeVlanConfObject.VlanId = &nativeVlanId
-				}
-				staticDhcpAddressV4 := nativeVlanConfItem["static_dhcp_address_v4"].(string)
-				if len(staticDhcpAddressV4) > 0 {
-					nativeVlanConfObject.StaticDhcpAddressV4 = &staticDhcpAddressV4
-				}
+				url1 := iPXEItem["url"].(string)
+				ipxeUrl := bmcapiclient.NullableString{}
+				ipxeUrl.Set(&url1)
+				rebootRequest.IpxeUrl = ipxeUrl
 			}
-			request.NativeVlanConfiguration = &nativeVlanConfObject
 		}
+	}
+	rebootRequest.BootType = &bootType
 
-		requestCommand := server.NewUpdateServerIPXECommand(client, serverID, *request)
-		_, err := requestCommand.Execute()
-		if err != nil {
-			return err
-		}
-	} else if d.HasChange("tags") {
-		tags := d.Get("tags").([]interface{})
-		client := m.(receiver.BMCSDK)
-		serverID := d.Id()
+	requestCommand := server.NewRebootServerCommand(client, serverID, *rebootRequest)
+	_, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+	return resourceWaitForCreate(serverID, &client)
+}
 
-		var request []bmcapiclient.TagAssignmentRequest
+// resourceServerReinstall installs the OS again on the same hardware. The server is deprovisioned while keeping
+// its reservation and IP blocks, and then provisioned again with the current configuration, keeping its ID.
+func resourceServerReinstall(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	serverID := d.Id()
 
-		if len(tags) > 0 {
-			request = make([]bmcapiclient.TagAssignmentRequest, len(tags))
+	m.(*providerMeta).locks.Lock(serverID)
+	defer m.(*providerMeta).locks.Unlock(serverID)
 
-			for i, j := range tags {
-				tarObject := bmcapiclient.TagAssignmentRequest{}
-				tagsItem := j.(map[string]interface{})
+	getCommand := server.NewGetServerCommand(client, serverID)
+	current, err := getCommand.Execute()
+	if err != nil {
+		return err
+	}
+	currentTags := serverTagAssignments(current.Tags)
+	previousTagsAll, _ := d.GetChange("tags_all")
+	tags := mergeDefaultTags(m.(*providerMeta).defaultTags, expandTagAssignments(d.Get("tags").([]interface{})))
+	tags = mergeUnmanagedTags(currentTags, previousTagsAll.(map[string]interface{}), tags)
 
-				tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
-				tagAssignItem := tagAssign.(map[string]interface{})
+	deleteIpBlocks := false
+	relinquishIpBlock := bmcapiclient.RelinquishIpBlock{}
+	relinquishIpBlock.DeleteIpBlocks = &deleteIpBlocks
+	deprovisionCommand := server.NewDeprovisionServerCommand(client, serverID, relinquishIpBlock)
+	_, err = deprovisionCommand.Execute()
+	if err != nil {
+		return err
+	}
+	waitResultError := resourceWaitForReserved(serverID, &client)
+	if waitResultError != nil {
+		return waitResultError
+	}
 
-				tarObject.Name = tagAssignItem["name"].(string)
-				value := tagAssignItem["value"].(string)
-				if len(value) > 0 {
-					tarObject.Value = &value
-				}
-				request[i] = tarObject
-			}
-		}
-		requestCommand := server.NewSetServerTagsCommand(client, serverID, request)
-		_, err := requestCommand.Execute()
-		if err != nil {
-			return err
-		}
-	} else if d.HasChange("hostname") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
-		serverID := d.Id()
-		request := &bmcapiclient.ServerPatch{}
-		var hostname = d.Get("hostname").(string)
-		request.Hostname = &hostname
-		var desc = d.Get("description").(string)
+	request := &bmcapiclient.ServerProvision{}
+	request.Hostname = d.Get("hostname").(string)
+	var desc = d.Get("description").(string)
+	if len(desc) > 0 {
 		request.Description = &desc
-		requestCommand := server.NewPatchServerCommand(client, serverID, *request)
-		_, err := requestCommand.Execute()
-		if err != nil {
-			return err
-		}
-	} else if d.HasChange("delete_ip_blocks") || d.HasChange("force") {
-		return resourceServerRead(d, m)
-	} else {
-		return fmt.Errorf("unsupported action")
 	}
-	return resourceServerRead(d, m)
+	request.Os = d.Get("os").(string)
+	var networkType = d.Get("network_type").(string)
+	if len(networkType) > 0 {
+		request.NetworkType = &networkType
+	}
+
+	var installDefault = d.Get("install_default_ssh_keys").(bool)
+	request.InstallDefaultSshKeys = &installDefault
+	temp := d.Get("ssh_keys").(*schema.Set).List()
+	keys := make([]string, len(temp))
+	for i, v := range temp {
+		keys[i] = fmt.Sprint(v)
+	}
+	request.SshKeys = keys
+
+	temp1 := d.Get("ssh_key_ids").(*schema.Set).List()
+	keyIds := make([]string, len(temp1))
+	for i, v := range temp1 {
+		keyIds[i] = fmt.Sprint(v)
+	}
+	request.SshKeyIds = keyIds
+
+	osConfiguration, err := expandServerOsConfiguration(d)
+	if err != nil {
+		return err
+	}
+	request.OsConfiguration = osConfiguration
+	if len(tags) > 0 {
+		request.Tags = expandServerTags(tags)
+	}
+	request.NetworkConfiguration = mergeUntrackedMemberships(expandServerNetworkConfiguration(d), current.NetworkConfiguration)
+	request.StorageConfiguration = expandServerStorageConfiguration(d)
+
+	query := &dto.Query{}
+	query.Force = d.Get("force").(bool)
+
+	requestCommand := server.NewProvisionServerCommandWithQuery(client, serverID, *request, query)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return fmt.Errorf("server (%s) was deprovisioned and remains reserved, but provisioning it again failed: %v", serverID, err)
+	}
+	d.Set("password", resp.Password)
+	if resp.OsConfiguration != nil {
+		d.Set("root_password", resp.OsConfiguration.RootPassword)
+		d.Set("management_ui_url", resp.OsConfiguration.ManagementUiUrl)
+	}
 
+	return resourceWaitForCreate(serverID, &client)
 }
 
-func resourceServerDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
-	serverID := d.Id()
+// customizeDiffServerReinstall plans changes to arguments in serverReinstallFields either as an in-place
+// reinstall of the OS, which requires a reserved server, or as a replacement of the server.
+func customizeDiffServerReinstall(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if len(d.Id()) == 0 {
+		return nil
+	}
+	var changed []string
+	for _, key := range serverReinstallFields {
+		if d.HasChange(key) {
+			changed = append(changed, key)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
 
-	var deleteIpBlocks = d.Get("delete_ip_blocks").(bool)
+	if d.Get("reinstall_strategy").(string) == serverReinstallStrategyReplace {
+		for _, key := range changed {
+			if err := d.ForceNew(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
-	relinquishIpBlock := bmcapiclient.RelinquishIpBlock{}
-	relinquishIpBlock.DeleteIpBlocks = &deleteIpBlocks
+	pricingModel := d.Get("pricing_model").(string)
+	if len(pricingModel) == 0 || pricingModel == "HOURLY" {
+		return fmt.Errorf("changing %s requires the OS to be installed again, which can be done in place only for servers "+
+			"with a reservation; set reinstall_strategy to %q to replace the server instead", strings.Join(changed, ", "), serverReinstallStrategyReplace)
+	}
 
-	requestCommand := server.NewDeprovisionServerCommand(client, serverID, relinquishIpBlock)
+	// The server is provisioned again, so a new provisioning date and credentials are expected.
+	for _, key := range []string{"provisioned_on", "password", "root_password"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	_, err := requestCommand.Execute()
+func resourceWaitForReserved(id string, client *receiver.BMCSDK) error {
+	log.Printf("Waiting for server %s to be deprovisioned to reserved...", id)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"powered-on", "powered-off", "rebooting", "resetting", "deleting"},
+		Target:     []string{"reserved"},
+		Refresh:    refreshForCreate(client, id),
+		Timeout:    pnapRetryTimeout,
+		Delay:      pnapRetryDelay,
+		MinTimeout: pnapRetryMinTimeout,
+	}
+
+	_, err := stateConf.WaitForState()
 	if err != nil {
-		return err
+		return fmt.Errorf("Error waiting for server (%s) to be reserved: %v", id, err)
 	}
 
 	return nil
@@ -1451,13 +1896,17 @@ func resourceWaitForPowerON(id string, client *receiver.BMCSDK) error {
 }
 
 func resourceWaitForPowerOff(id string, client *receiver.BMCSDK) error {
+	return resourceWaitForPowerOffWithTimeout(id, client, pnapRetryTimeout)
+}
+
+func resourceWaitForPowerOffWithTimeout(id string, client *receiver.BMCSDK, timeout time.Duration) error {
 	log.Printf("Waiting for server %s to power off...", id)
 
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{"powered-on"},
 		Target:     []string{"powered-off"},
 		Refresh:    refreshForCreate(client, id),
-		Timeout:    pnapRetryTimeout,
+		Timeout:    timeout,
 		Delay:      pnapRetryDelay,
 		MinTimeout: pnapRetryMinTimeout,
 	}
@@ -1716,7 +2165,7 @@ func flattenNetworkConfiguration(netConf *bmcapiclient.NetworkConfiguration, ncI
 								}
 
 								if j.ComputeSlaacIp != nil {
-									spnItem["dhcp"] = *j.ComputeSlaacIp
+									spnItem["compute_slaac_ip"] = *j.ComputeSlaacIp
 								}
 								if j.StatusDescription != nil {
 									spnItem["status_description"] = *j.StatusDescription
@@ -1774,6 +2223,43 @@ func flattenServerTags(tagsRead []bmcapiclient.TagAssignment, tagsInput []interf
 	return tagsInput
 }
 
+// getServerTags reads the tags currently assigned to the server.
+func getServerTags(client receiver.BMCSDK, serverID string) ([]tagAssignment, error) {
+	requestCommand := server.NewGetServerCommand(client, serverID)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return nil, err
+	}
+	return serverTagAssignments(resp.Tags), nil
+}
+
+// serverTagAssignments converts the tags assigned to a server to tag assignments.
+func serverTagAssignments(tagsRead []bmcapiclient.TagAssignment) []tagAssignment {
+	tags := make([]tagAssignment, len(tagsRead))
+	for i, j := range tagsRead {
+		tags[i].name = j.Name
+		if j.Value != nil {
+			tags[i].value = *j.Value
+		}
+	}
+	return tags
+}
+
+// expandServerTags converts tag assignments to server tag assignment requests.
+func expandServerTags(tags []tagAssignment) []bmcapiclient.TagAssignmentRequest {
+	tagsObject := make([]bmcapiclient.TagAssignmentRequest, len(tags))
+	for i, j := range tags {
+		tarObject := bmcapiclient.TagAssignmentRequest{}
+		tarObject.Name = j.name
+		if len(j.value) > 0 {
+			value := j.value
+			tarObject.Value = &value
+		}
+		tagsObject[i] = tarObject
+	}
+	return tagsObject
+}
+
 func supressUserDefinedNetworkType(k, oldValue, newValue string, d *schema.ResourceData) bool {
 	if len(oldValue) > 0 && newValue == "USER_DEFINED" {
 		return true
@@ -0,0 +1,153 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+func resourceServerPrivateNetwork() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceServerPrivateNetworkCreate,
+		Read:   resourceServerPrivateNetworkRead,
+		Delete: resourceServerPrivateNetworkDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(pnapRetryTimeout),
+			Delete: schema.DefaultTimeout(pnapDeleteRetryTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ips": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"dhcp": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"status_description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vlan_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerNetworkImport,
+		},
+	}
+}
+
+func resourceServerPrivateNetworkCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("network_id").(string)
+
+	request := &bmcapiclient.ServerPrivateNetwork{}
+	request.Id = networkID
+	temp := d.Get("ips").(*schema.Set).List()
+	if len(temp) > 0 {
+		ips := make([]string, len(temp))
+		for i, v := range temp {
+			ips[i] = fmt.Sprint(v)
+		}
+		request.Ips = ips
+	}
+	dhcp := d.Get("dhcp").(bool)
+	request.Dhcp = &dhcp
+
+	m.(*providerMeta).locks.Lock(serverID)
+	requestCommand := server.NewAddServerPrivateNetworkCommand(client, serverID, *request)
+	_, err := requestCommand.Execute()
+	m.(*providerMeta).locks.Unlock(serverID)
+	if err != nil {
+		return err
+	}
+	d.SetId(serverID + "/" + networkID)
+
+	waitResultError := serverNetworkWaitForStatus(&client, serverID, serverNetworkTypePrivate, networkID, serverNetworkStatusAssigned, d.Timeout(schema.TimeoutCreate))
+	if waitResultError != nil {
+		return waitResultError
+	}
+
+	return resourceServerPrivateNetworkRead(d, m)
+}
+
+func resourceServerPrivateNetworkRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	membership, err := getServerNetworkMembership(client, d.Get("server_id").(string), serverNetworkTypePrivate, d.Get("network_id").(string))
+	if isNotFoundError(err) {
+		// The server was deleted, which removed it from the network.
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if membership == nil {
+		// The server is no longer a member of the network.
+		d.SetId("")
+		return nil
+	}
+	d.Set("ips", flattenServerNetworkIps(membership.ips, d.Get("ips").(*schema.Set)))
+	if membership.dhcp != nil {
+		d.Set("dhcp", *membership.dhcp)
+	}
+	d.Set("status_description", membership.statusDescription)
+	if membership.vlanID != nil {
+		d.Set("vlan_id", *membership.vlanID)
+	}
+	return nil
+}
+
+func resourceServerPrivateNetworkDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("network_id").(string)
+
+	m.(*providerMeta).locks.Lock(serverID)
+	requestCommand := server.NewRemoveServerPrivateNetworkCommand(client, serverID, networkID)
+	_, err := requestCommand.Execute()
+	m.(*providerMeta).locks.Unlock(serverID)
+	if isNotFoundError(err) {
+		// The server is gone, or it is no longer a member of the network.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return serverNetworkWaitForStatus(&client, serverID, serverNetworkTypePrivate, networkID, serverNetworkStatusUnassigned, d.Timeout(schema.TimeoutDelete))
+}
+
+func resourceServerNetworkImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected server_id/network_id", d.Id())
+	}
+	d.Set("server_id", parts[0])
+	d.Set("network_id", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,151 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+func resourceServerPublicNetwork() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceServerPublicNetworkCreate,
+		Read:   resourceServerPublicNetworkRead,
+		Delete: resourceServerPublicNetworkDelete,
+
+		CustomizeDiff: customizeDiffServerPublicNetworkIps,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(pnapRetryTimeout),
+			Delete: schema.DefaultTimeout(pnapDeleteRetryTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ips": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"compute_slaac_ip": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+			"status_description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vlan_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerNetworkImport,
+		},
+	}
+}
+
+// customizeDiffServerPublicNetworkIps checks during plan that ips are set unless compute_slaac_ip is true,
+// since the API assigns a server to a public network without IPs only when forced to.
+func customizeDiffServerPublicNetworkIps(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	ips, computeSlaacIp := d.GetRawConfig().GetAttr("ips"), d.GetRawConfig().GetAttr("compute_slaac_ip")
+	if len(d.Id()) > 0 || !ips.IsKnown() || !computeSlaacIp.IsKnown() {
+		return nil
+	}
+	if (!ips.IsNull() && ips.LengthInt() > 0) || (!computeSlaacIp.IsNull() && computeSlaacIp.True()) {
+		return nil
+	}
+	return fmt.Errorf("ips must be set unless compute_slaac_ip is true")
+}
+
+func resourceServerPublicNetworkCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("network_id").(string)
+
+	request := &bmcapiclient.ServerPublicNetwork{}
+	request.Id = networkID
+	temp := d.Get("ips").(*schema.Set).List()
+	ips := make([]string, len(temp))
+	for i, v := range temp {
+		ips[i] = fmt.Sprint(v)
+	}
+	request.Ips = ips
+	computeSlaacIp := d.Get("compute_slaac_ip").(bool)
+	request.ComputeSlaacIp = &computeSlaacIp
+
+	m.(*providerMeta).locks.Lock(serverID)
+	requestCommand := server.NewAddServerPublicNetworkCommand(client, serverID, *request)
+	_, err := requestCommand.Execute()
+	m.(*providerMeta).locks.Unlock(serverID)
+	if err != nil {
+		return err
+	}
+	d.SetId(serverID + "/" + networkID)
+
+	waitResultError := serverNetworkWaitForStatus(&client, serverID, serverNetworkTypePublic, networkID, serverNetworkStatusAssigned, d.Timeout(schema.TimeoutCreate))
+	if waitResultError != nil {
+		return waitResultError
+	}
+
+	return resourceServerPublicNetworkRead(d, m)
+}
+
+func resourceServerPublicNetworkRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	membership, err := getServerNetworkMembership(client, d.Get("server_id").(string), serverNetworkTypePublic, d.Get("network_id").(string))
+	if isNotFoundError(err) {
+		// The server was deleted, which removed it from the network.
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if membership == nil {
+		// The server is no longer a member of the network.
+		d.SetId("")
+		return nil
+	}
+	d.Set("ips", flattenServerNetworkIps(membership.ips, d.Get("ips").(*schema.Set)))
+	d.Set("status_description", membership.statusDescription)
+	if membership.vlanID != nil {
+		d.Set("vlan_id", *membership.vlanID)
+	}
+	return nil
+}
+
+func resourceServerPublicNetworkDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("network_id").(string)
+
+	m.(*providerMeta).locks.Lock(serverID)
+	requestCommand := server.NewRemoveServerPublicNetworkCommand(client, serverID, networkID)
+	_, err := requestCommand.Execute()
+	m.(*providerMeta).locks.Unlock(serverID)
+	if isNotFoundError(err) {
+		// The server is gone, or it is no longer a member of the network.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return serverNetworkWaitForStatus(&client, serverID, serverNetworkTypePublic, networkID, serverNetworkStatusUnassigned, d.Timeout(schema.TimeoutDelete))
+}
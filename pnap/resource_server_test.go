@@ -7,10 +7,10 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	helperserver "github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
 )
 
@@ -143,10 +143,63 @@ func TestAccPnapServer_shutdowntest(t *testing.T) {
 	})
 }
 
+func TestAccPnapServer_powerState(t *testing.T) {
+	var server bmcapiclient.Server
+	rNameSuffix := acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rName := "acctest-" + rNameSuffix
+	rLine := "pnap_server." + rName
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckServerResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				// use configuration for server creation
+				Config: testAccPowerStateServerResource(rName, "on", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServerExists(rLine, &server),
+					testAccCheckServerAttributes(rName, &server),
+					resource.TestCheckResourceAttr(rLine, "power_state", "on"),
+					resource.TestCheckResourceAttr(rLine, "status", "powered-on"),
+				),
+			},
+			{
+				// power off the server, the plan should be empty afterwards
+				Config: testAccPowerStateServerResource(rName, "off", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServerExists(rLine, &server),
+					testAccCheckServerStatusAttribute(rName, &server),
+					resource.TestCheckResourceAttr(rLine, "power_state", "off"),
+					resource.TestCheckResourceAttr(rLine, "status", "powered-off"),
+				),
+			},
+			{
+				// power the server on again
+				Config: testAccPowerStateServerResource(rName, "on", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServerExists(rLine, &server),
+					testAccCheckServerAttributes(rName, &server),
+					resource.TestCheckResourceAttr(rLine, "power_state", "on"),
+				),
+			},
+			{
+				// change the reboot trigger to reboot the server
+				Config: testAccPowerStateServerResource(rName, "on", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServerExists(rLine, &server),
+					testAccCheckServerAttributes(rName, &server),
+					resource.TestCheckResourceAttr(rLine, "reboot_trigger", "2"),
+					resource.TestCheckResourceAttr(rLine, "status", "powered-on"),
+				),
+			},
+		},
+	})
+}
+
 // testAccPreCheck validates the necessary test API keys exist
 // in the testing environment
 func testAccPreCheck(t *testing.T) {
-	//client := testAccProvider.Meta().(receiver.BMCSDK)
+	//client := testAccProvider.Meta().(*providerMeta).client
 	/* err := client..VerifyConfiguration()
 	if err != nil {
 		t.Fatal(err)
@@ -157,7 +210,7 @@ func testAccPreCheck(t *testing.T) {
 // has been destroyed
 func testAccCheckServerResourceDestroy(s *terraform.State) error {
 	// get configured client from metadata
-	client := testAccProvider.Meta().(receiver.BMCSDK)
+	client := testAccProvider.Meta().(*providerMeta).client
 	// loop through the resources in state, verifying each server
 	// is destroyed
 	for _, rs := range s.RootModule().Resources {
@@ -277,6 +330,22 @@ resource "pnap_server" "%s" {
 }`, rName, rName)
 }
 
+func testAccPowerStateServerResource(rName string, powerState string, rebootTrigger string) string {
+	return fmt.Sprintf(`
+resource "pnap_server" "%s" {
+	hostname = "%s"
+	os = "ubuntu/jammy"
+	type = "s1.c1.medium"
+	location = "PHX"
+	ssh_keys = [
+		"ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQDF9LdAFElNCi7JoWh6KUcchrJ2Gac1aqGRPpdZNowObpRtmiRCecAMb7bUgNAaNfcmwiQi7tos9TlnFgprIcfMWb8MSs3ABYHmBgqEEt3RWYf0fAc9CsIpJdMCUG28TPGTlRXCEUVNKgLMdcseAlJoGp1CgbHWIN65fB3he3kAZcfpPn5mapV0tsl2p+ZyuAGRYdn5dJv2RZDHUZBkOeUobwsij+weHCKAFmKQKtCP7ybgVHaQjAPrj8MGnk1jBbjDt5ws+Be+9JNjQJee9zCKbAOsIo3i+GcUIkrw5jxPU/RTGlWBcemPaKHdciSzGcjWboapzIy49qypQhZe1U75 user2@172.16.1.106"
+	]
+	power_state = "%s"
+	graceful_shutdown = true
+	reboot_trigger = "%s"
+}`, rName, rName, powerState, rebootTrigger)
+}
+
 // testAccCheckServerExists uses the SDK directly to retrieve
 // the server, and stores it in the provided
 // *dto.LongServer
@@ -293,7 +362,7 @@ func testAccCheckServerExists(resourceName string, server *bmcapiclient.Server)
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(receiver.BMCSDK)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		requestCommand := helperserver.NewGetServerCommand(client, rs.Primary.ID)
 
@@ -376,7 +445,7 @@ func init() {
 		F: func(region string) error {
 
 			// retrieve the configured client from the test setup
-			client := testAccProvider.Meta().(receiver.BMCSDK)
+			client := testAccProvider.Meta().(*providerMeta).client
 
 			requestCommand := helperserver.NewGetServersCommand(client)
 			resp, err := requestCommand.Execute()
@@ -410,3 +479,117 @@ func init() {
 		},
 	})
 }
+
+func TestMergeUntrackedMemberships_reinstall(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceServer().Schema, map[string]interface{}{
+		"network_configuration": []interface{}{
+			map[string]interface{}{
+				"private_network_configuration": []interface{}{
+					map[string]interface{}{
+						"configuration_type": "USER_DEFINED",
+						"private_networks": []interface{}{
+							map[string]interface{}{
+								"server_private_network": []interface{}{
+									map[string]interface{}{"id": "private-1", "ips": []interface{}{"10.0.0.11"}},
+								},
+							},
+						},
+					},
+				},
+				"public_network_configuration": []interface{}{
+					map[string]interface{}{
+						"public_networks": []interface{}{
+							map[string]interface{}{
+								"server_public_network": []interface{}{
+									map[string]interface{}{"id": "public-1", "ips": []interface{}{"182.16.0.146"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// private-2 and public-2 were added with pnap_server_private_network and pnap_server_public_network.
+	configurationType := "USER_DEFINED"
+	dhcp := false
+	current := bmcapiclient.NetworkConfiguration{
+		PrivateNetworkConfiguration: &bmcapiclient.PrivateNetworkConfiguration{
+			ConfigurationType: &configurationType,
+			PrivateNetworks: []bmcapiclient.ServerPrivateNetwork{
+				{Id: "private-1", Ips: []string{"10.0.0.11"}},
+				{Id: "private-2", Ips: []string{"10.0.1.11"}, Dhcp: &dhcp},
+			},
+		},
+		PublicNetworkConfiguration: &bmcapiclient.PublicNetworkConfiguration{
+			PublicNetworks: []bmcapiclient.ServerPublicNetwork{
+				{Id: "public-1", Ips: []string{"182.16.0.146"}},
+				{Id: "public-2", Ips: []string{"182.16.1.146"}},
+			},
+		},
+	}
+
+	request := mergeUntrackedMemberships(expandServerNetworkConfiguration(d), current)
+	if request == nil || request.PrivateNetworkConfiguration == nil || request.PublicNetworkConfiguration == nil {
+		t.Fatalf("expected private and public network configuration in the request, got %+v", request)
+	}
+	var privateIds, publicIds []string
+	for _, j := range request.PrivateNetworkConfiguration.PrivateNetworks {
+		privateIds = append(privateIds, j.Id)
+	}
+	for _, j := range request.PublicNetworkConfiguration.PublicNetworks {
+		publicIds = append(publicIds, j.Id)
+	}
+	if strings.Join(privateIds, ",") != "private-1,private-2" || strings.Join(publicIds, ",") != "public-1,public-2" {
+		t.Errorf("expected all memberships in the request, got private %v and public %v", privateIds, publicIds)
+	}
+	if ips := request.PrivateNetworkConfiguration.PrivateNetworks[1].Ips; len(ips) != 1 || ips[0] != "10.0.1.11" {
+		t.Errorf("expected the IPs of private-2 to be kept, got %v", ips)
+	}
+}
+
+func TestMergeUntrackedMemberships_noNetworkConfiguration(t *testing.T) {
+	if request := mergeUntrackedMemberships(nil, bmcapiclient.NetworkConfiguration{}); request != nil {
+		t.Errorf("expected no network configuration without memberships, got %+v", request)
+	}
+
+	current := bmcapiclient.NetworkConfiguration{
+		PublicNetworkConfiguration: &bmcapiclient.PublicNetworkConfiguration{
+			PublicNetworks: []bmcapiclient.ServerPublicNetwork{{Id: "public-2", Ips: []string{"182.16.1.146"}}},
+		},
+	}
+	request := mergeUntrackedMemberships(nil, current)
+	if request == nil || request.PublicNetworkConfiguration == nil || len(request.PublicNetworkConfiguration.PublicNetworks) != 1 {
+		t.Fatalf("expected public-2 in the request, got %+v", request)
+	}
+	if request.PrivateNetworkConfiguration != nil {
+		t.Errorf("expected no private network configuration, got %+v", request.PrivateNetworkConfiguration)
+	}
+}
+
+func TestFlattenNetworkConfiguration_untrackedMemberships(t *testing.T) {
+	netConf := &bmcapiclient.NetworkConfiguration{
+		PrivateNetworkConfiguration: &bmcapiclient.PrivateNetworkConfiguration{
+			PrivateNetworks: []bmcapiclient.ServerPrivateNetwork{{Id: "private-2", Ips: []string{"10.0.1.11"}}},
+		},
+		PublicNetworkConfiguration: &bmcapiclient.PublicNetworkConfiguration{
+			PublicNetworks: []bmcapiclient.ServerPublicNetwork{{Id: "public-2", Ips: []string{"182.16.1.146"}}},
+		},
+	}
+
+	// Memberships that are not configured are read, so that they show as drift.
+	ncInput := []interface{}{
+		map[string]interface{}{
+			"private_network_configuration": []interface{}{},
+			"public_network_configuration":  []interface{}{},
+		},
+	}
+	nc := flattenNetworkConfiguration(netConf, ncInput)[0].(map[string]interface{})
+	if pn := nc["private_network_configuration"].([]interface{})[0].(map[string]interface{})["private_networks"].([]interface{}); len(pn) != 1 {
+		t.Errorf("expected private-2, got %v", pn)
+	}
+	if pn := nc["public_network_configuration"].([]interface{})[0].(map[string]interface{})["public_networks"].([]interface{}); len(pn) != 1 {
+		t.Errorf("expected public-2, got %v", pn)
+	}
+}
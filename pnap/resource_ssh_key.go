@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/sshkey"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
@@ -57,7 +56,7 @@ func resourceSshKey() *schema.Resource {
 
 func resourceSshKeyCreate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &bmcapiclient.SshKeyCreate{}
 	request.Name = d.Get("name").(string)
@@ -86,7 +85,7 @@ func resourceSshKeyCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceSshKeyRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	keyID := d.Id()
 	requestCommand := sshkey.NewGetSshKeyCommand(client, keyID)
 	resp, err := requestCommand.Execute()
@@ -114,7 +113,7 @@ func resourceSshKeyRead(d *schema.ResourceData, m interface{}) error {
 
 func resourceSshKeyUpdate(d *schema.ResourceData, m interface{}) error {
 	if d.HasChange("name") || d.HasChange("default") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		//var requestCommand command.Executor
 
 		request := &bmcapiclient.SshKeyUpdate{}
@@ -142,7 +141,7 @@ func resourceSshKeyUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceSshKeyDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	sshKeyID := d.Id()
 
@@ -3,6 +3,7 @@ package pnap
 import (
 	"fmt"
 	"log"
+	"reflect"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -212,7 +213,7 @@ func resourceStorageNetwork() *schema.Resource {
 
 func resourceStorageNetworkCreate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &networkstorageapiclient.StorageNetworkCreate{}
 	request.Name = d.Get("name").(string)
@@ -250,23 +251,17 @@ func resourceStorageNetworkCreate(d *schema.ResourceData, m interface{}) error {
 				}
 				volumeObject.CapacityInGb = int32(volumeItem["capacity_in_gb"].(int))
 
-				tags := volumeItem["tags"].([]interface{})
+				tags := mergeDefaultTags(m.(*providerMeta).defaultTags, expandTagAssignments(volumeItem["tags"].([]interface{})))
 				if len(tags) > 0 {
 					tagsObject := make([]networkstorageapiclient.TagAssignmentRequest, len(tags))
 					for i, j := range tags {
 						tarObject := networkstorageapiclient.TagAssignmentRequest{}
-						tagsItem := j.(map[string]interface{})
-						if tagsItem["tag_assignment"] != nil && len(tagsItem["tag_assignment"].([]interface{})) > 0 {
-							tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
-							tagAssignItem := tagAssign.(map[string]interface{})
-
-							tarObject.Name = tagAssignItem["name"].(string)
-							value := tagAssignItem["value"].(string)
-							if len(value) > 0 {
-								tarObject.Value = &value
-							}
-							tagsObject[i] = tarObject
+						tarObject.Name = j.name
+						if len(j.value) > 0 {
+							value := j.value
+							tarObject.Value = &value
 						}
+						tagsObject[i] = tarObject
 					}
 					volumeObject.Tags = tagsObject
 				}
@@ -295,7 +290,7 @@ func resourceStorageNetworkCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceStorageNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	storageNetworkID := d.Id()
 	requestCommand := storagenetwork.NewGetStorageNetworkCommand(client, storageNetworkID)
 	resp, err := requestCommand.Execute()
@@ -344,8 +339,15 @@ func resourceStorageNetworkRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceStorageNetworkUpdate(d *schema.ResourceData, m interface{}) error {
+	volumeTagsChanged := d.HasChange("volumes")
+	if volumeTagsChanged {
+		oldVolumes, newVolumes := d.GetChange("volumes")
+		if !onlyVolumeTagsChanged(oldVolumes.([]interface{}), newVolumes.([]interface{})) {
+			return fmt.Errorf("unsupported action")
+		}
+	}
 	if d.HasChange("name") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		storageNetworkID := d.Id()
 		request := &networkstorageapiclient.StorageNetworkUpdate{}
 		var name = d.Get("name").(string)
@@ -357,14 +359,118 @@ func resourceStorageNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 		if err != nil {
 			return err
 		}
-	} else {
+	} else if !volumeTagsChanged {
 		return fmt.Errorf("unsupported action")
 	}
+	// Volume tags are applied together with the other changes.
+	if volumeTagsChanged {
+		err := resourceStorageNetworkUpdateVolumeTags(d, m)
+		if err != nil {
+			return err
+		}
+	}
 	return resourceStorageNetworkRead(d, m)
 }
 
+// onlyVolumeTagsChanged checks whether the volumes differ in their tags only, which is the one volume change
+// that can be applied to an existing storage network.
+func onlyVolumeTagsChanged(oldVolumes []interface{}, newVolumes []interface{}) bool {
+	if len(oldVolumes) != len(newVolumes) {
+		return false
+	}
+	for i := range newVolumes {
+		oldVolume, newVolume := expandVolumeItem(oldVolumes[i]), expandVolumeItem(newVolumes[i])
+		if oldVolume == nil || newVolume == nil {
+			if oldVolume != nil || newVolume != nil {
+				return false
+			}
+			continue
+		}
+		for _, k := range []string{"name", "description", "capacity_in_gb"} {
+			if oldVolume[k] != newVolume[k] {
+				return false
+			}
+		}
+		// path_suffix is computed when it is not configured.
+		if newVolume["path_suffix"] != "" && oldVolume["path_suffix"] != newVolume["path_suffix"] {
+			return false
+		}
+	}
+	return true
+}
+
+// expandVolumeItem returns the volume block of an item of volumes.
+func expandVolumeItem(v interface{}) map[string]interface{} {
+	volumesItem, ok := v.(map[string]interface{})
+	if !ok || volumesItem["volume"] == nil || len(volumesItem["volume"].([]interface{})) == 0 {
+		return nil
+	}
+	volumeItem, _ := volumesItem["volume"].([]interface{})[0].(map[string]interface{})
+	return volumeItem
+}
+
+// resourceStorageNetworkUpdateVolumeTags assigns the volume tags merged with the provider default tags to each
+// volume whose tags changed, keeping the tags that were assigned to the volume by other means.
+func resourceStorageNetworkUpdateVolumeTags(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	storageNetworkID := d.Id()
+	defaultTags := m.(*providerMeta).defaultTags
+
+	requestCommand := storagenetwork.NewGetStorageNetworkCommand(client, storageNetworkID)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return err
+	}
+
+	oldVolumes, newVolumes := d.GetChange("volumes")
+	for i, j := range newVolumes.([]interface{}) {
+		newVolume := expandVolumeItem(j)
+		oldVolume := expandVolumeItem(oldVolumes.([]interface{})[i])
+		if newVolume == nil || oldVolume == nil || reflect.DeepEqual(newVolume["tags"], oldVolume["tags"]) {
+			continue
+		}
+		volumeID, _ := oldVolume["id"].(string)
+		var current []tagAssignment
+		for _, v := range resp.Volumes {
+			if v.Id != nil && *v.Id == volumeID {
+				for _, l := range v.Tags {
+					tag := tagAssignment{name: l.Name}
+					if l.Value != nil {
+						tag.value = *l.Value
+					}
+					current = append(current, tag)
+				}
+			}
+		}
+		oldTags := expandTagAssignments(oldVolume["tags"].([]interface{}))
+		tags := mergeDefaultTags(defaultTags, expandTagAssignments(newVolume["tags"].([]interface{})))
+		for _, tag := range current {
+			if !isManagedTag(tag.name, oldTags, defaultTags, nil) && !containsTag(tags, tag.name) {
+				tags = append(tags, tag)
+			}
+		}
+
+		request := make([]networkstorageapiclient.TagAssignmentRequest, len(tags))
+		for k, tag := range tags {
+			tarObject := networkstorageapiclient.TagAssignmentRequest{}
+			tarObject.Name = tag.name
+			if len(tag.value) > 0 {
+				value := tag.value
+				tarObject.Value = &value
+			}
+			request[k] = tarObject
+		}
+		tagsCommand := storagenetwork.NewUpdateStorageNetworkVolumeTagsCommand(client, storageNetworkID, volumeID, request)
+		_, err = tagsCommand.Execute()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func resourceStorageNetworkDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	storageNetworkID := d.Id()
 
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/tagapi/tag"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	tagapiclient "github.com/phoenixnap/go-sdk-bmc/tagapi/v3"
@@ -70,7 +69,7 @@ func resourceTag() *schema.Resource {
 
 func resourceTagCreate(d *schema.ResourceData, m interface{}) error {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &tagapiclient.TagCreate{}
 	request.Name = d.Get("name").(string)
@@ -92,7 +91,7 @@ func resourceTagCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceTagRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 	tagID := d.Id()
 	requestCommand := tag.NewGetTagCommand(client, tagID)
 	resp, err := requestCommand.Execute()
@@ -134,7 +133,7 @@ func resourceTagRead(d *schema.ResourceData, m interface{}) error {
 
 func resourceTagUpdate(d *schema.ResourceData, m interface{}) error {
 	if d.HasChange("name") || d.HasChange("is_billing_tag") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		tagID := d.Id()
 
 		request := &tagapiclient.TagUpdate{}
@@ -156,7 +155,7 @@ func resourceTagUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceTagDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	tagID := d.Id()
 
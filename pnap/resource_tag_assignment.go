@@ -0,0 +1,229 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+	"github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
+	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	tagAssignmentResourceTypeServer  = "server"
+	tagAssignmentResourceTypeIpBlock = "ip-block"
+)
+
+func resourceTagAssignment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTagAssignmentCreate,
+		Read:   resourceTagAssignmentRead,
+		Update: resourceTagAssignmentUpdate,
+		Delete: resourceTagAssignmentDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(pnapRetryTimeout),
+			Update: schema.DefaultTimeout(pnapRetryTimeout),
+			Delete: schema.DefaultTimeout(pnapDeleteRetryTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"resource_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"resource_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					tagAssignmentResourceTypeServer,
+					tagAssignmentResourceTypeIpBlock,
+				}, false),
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tag_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_billing_tag": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"created_by": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTagAssignmentImport,
+		},
+	}
+}
+
+func resourceTagAssignmentCreate(d *schema.ResourceData, m interface{}) error {
+	resourceType := d.Get("resource_type").(string)
+	resourceID := d.Get("resource_id").(string)
+	tag := tagAssignment{
+		name:  d.Get("name").(string),
+		value: d.Get("value").(string),
+	}
+
+	err := updateResourceTags(m.(*providerMeta), resourceType, resourceID, func(tags []tagAssignment) []tagAssignment {
+		return setTag(tags, tag)
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resourceType + "/" + resourceID + "/" + tag.name)
+
+	return resourceTagAssignmentRead(d, m)
+}
+
+func resourceTagAssignmentRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	resourceType := d.Get("resource_type").(string)
+	resourceID := d.Get("resource_id").(string)
+	name := d.Get("name").(string)
+
+	switch resourceType {
+	case tagAssignmentResourceTypeServer:
+		requestCommand := server.NewGetServerCommand(client, resourceID)
+		resp, err := requestCommand.Execute()
+		if isNotFoundError(err) {
+			// The server was deleted, taking the tag assignment with it.
+			d.SetId("")
+			return nil
+		} else if err != nil {
+			return err
+		}
+		for _, v := range resp.Tags {
+			if v.Name == name {
+				d.Set("tag_id", v.Id)
+				d.Set("value", v.Value)
+				d.Set("is_billing_tag", v.IsBillingTag)
+				d.Set("created_by", v.CreatedBy)
+				return nil
+			}
+		}
+	case tagAssignmentResourceTypeIpBlock:
+		requestCommand := ipblock.NewGetIpBlockCommand(client, resourceID)
+		resp, err := requestCommand.Execute()
+		if isNotFoundError(err) {
+			// The IP Block was deleted, taking the tag assignment with it.
+			d.SetId("")
+			return nil
+		} else if err != nil {
+			return err
+		}
+		for _, v := range resp.Tags {
+			if v.Name == name {
+				d.Set("tag_id", v.Id)
+				d.Set("value", v.Value)
+				d.Set("is_billing_tag", v.IsBillingTag)
+				d.Set("created_by", v.CreatedBy)
+				return nil
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported resource type %s", resourceType)
+	}
+
+	// The tag is no longer assigned to the resource.
+	d.SetId("")
+	return nil
+}
+
+func resourceTagAssignmentUpdate(d *schema.ResourceData, m interface{}) error {
+	if d.HasChange("value") {
+		tag := tagAssignment{
+			name:  d.Get("name").(string),
+			value: d.Get("value").(string),
+		}
+		err := updateResourceTags(m.(*providerMeta), d.Get("resource_type").(string), d.Get("resource_id").(string), func(tags []tagAssignment) []tagAssignment {
+			return setTag(tags, tag)
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		return fmt.Errorf("unsupported action")
+	}
+	return resourceTagAssignmentRead(d, m)
+}
+
+func resourceTagAssignmentDelete(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("name").(string)
+	err := updateResourceTags(m.(*providerMeta), d.Get("resource_type").(string), d.Get("resource_id").(string), func(tags []tagAssignment) []tagAssignment {
+		return removeTag(tags, name)
+	})
+	if isNotFoundError(err) {
+		// The tagged resource is already gone.
+		return nil
+	}
+	return err
+}
+
+func resourceTagAssignmentImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 3)
+	if len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 || len(parts[2]) == 0 {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected resource_type/resource_id/name", d.Id())
+	}
+	d.Set("resource_type", parts[0])
+	d.Set("resource_id", parts[1])
+	d.Set("name", parts[2])
+	return []*schema.ResourceData{d}, nil
+}
+
+// updateResourceTags reads the tags assigned to a resource, modifies them and writes them back.
+// Writes to the same resource are serialized so that concurrent assignments do not overwrite each other.
+func updateResourceTags(meta *providerMeta, resourceType string, resourceID string, modify func([]tagAssignment) []tagAssignment) error {
+	client := meta.client
+
+	meta.locks.Lock(resourceID)
+	defer meta.locks.Unlock(resourceID)
+
+	switch resourceType {
+	case tagAssignmentResourceTypeServer:
+		currentTags, err := getServerTags(client, resourceID)
+		if err != nil {
+			return err
+		}
+		return setServerTags(client, resourceID, modify(currentTags))
+	case tagAssignmentResourceTypeIpBlock:
+		currentTags, err := getIpBlockTags(client, resourceID)
+		if err != nil {
+			return err
+		}
+		return setIpBlockTags(client, resourceID, modify(currentTags))
+	default:
+		return fmt.Errorf("unsupported resource type %s", resourceType)
+	}
+}
+
+// setServerTags replaces the tags assigned to the server.
+func setServerTags(client receiver.BMCSDK, serverID string, tags []tagAssignment) error {
+	requestCommand := server.NewSetServerTagsCommand(client, serverID, expandServerTags(tags))
+	_, err := requestCommand.Execute()
+	return err
+}
+
+// setIpBlockTags replaces the tags assigned to the IP Block.
+func setIpBlockTags(client receiver.BMCSDK, ipBlockID string, tags []tagAssignment) error {
+	requestCommand := ipblock.NewPutTagsIpBlockCommand(client, ipBlockID, expandIpBlockTags(tags))
+	_, err := requestCommand.Execute()
+	return err
+}
@@ -0,0 +1,136 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/product"
+	"github.com/PNAP/go-sdk-helper-bmc/dto"
+	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// serverDemand counts the servers of each type and location planned for creation by a provider instance,
+// so that servers created with count or for_each are checked against product availability together.
+// The SDK does not pass the resource address to CustomizeDiff, so each planned instance is counted: a provider
+// instance plans every resource instance once, and instances sharing a hostname are still separate servers.
+type serverDemand struct {
+	lock    sync.Mutex
+	planned map[string]int
+}
+
+// newServerDemand returns an empty serverDemand.
+func newServerDemand() *serverDemand {
+	return &serverDemand{
+		planned: make(map[string]int),
+	}
+}
+
+// add records a planned server and returns the number of servers of the type planned in the location.
+func (s *serverDemand) add(serverType string, location string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := serverType + "/" + location
+	s.planned[key]++
+	return s.planned[key]
+}
+
+// serverAvailability holds the availability of a server type in a location.
+type serverAvailability struct {
+	available         bool
+	availableQuantity int
+}
+
+// getServerAvailability queries product availability for quantity servers of the type in the given locations.
+func getServerAvailability(client receiver.BMCSDK, serverType string, locations []string, quantity int) (map[string]serverAvailability, error) {
+	query := dto.ProductAvailabilityQuery{}
+	query.ProductCategory = []string{"SERVER"}
+	query.ProductCode = []string{serverType}
+	query.Location = locations
+	query.MinQuantity = float32(quantity)
+
+	requestCommand := product.NewGetProductAvailabilityCommand(client, query)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return nil, err
+	}
+	availability := make(map[string]serverAvailability)
+	for _, j := range resp {
+		if j.ProductCode != serverType {
+			continue
+		}
+		for _, l := range j.LocationAvailabilityDetails {
+			availability[string(l.Location)] = serverAvailability{
+				available:         l.MinQuantityAvailable,
+				availableQuantity: int(l.AvailableQuantity),
+			}
+		}
+	}
+	return availability, nil
+}
+
+// selectServerLocation returns the first of the preferred locations in which the server type is in stock.
+func selectServerLocation(client receiver.BMCSDK, serverType string, preferences []string) (string, error) {
+	availability, err := getServerAvailability(client, serverType, preferences, 1)
+	if err != nil {
+		return "", fmt.Errorf("error checking availability of %s servers in %s: %v", serverType, strings.Join(preferences, ", "), err)
+	}
+	for _, location := range preferences {
+		if availability[location].available {
+			return location, nil
+		}
+	}
+	return "", fmt.Errorf("no %s servers available in any of the preferred locations %s", serverType, strings.Join(preferences, ", "))
+}
+
+// customizeDiffServerAvailability checks during plan that the server type is in stock in the location,
+// or in one of the preferred locations, when availability is required by the server or the provider.
+// Servers planned together count towards the requested quantity.
+func customizeDiffServerAvailability(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if len(d.Id()) > 0 {
+		return nil
+	}
+	meta := m.(*providerMeta)
+	requireAvailability := meta.requireAvailability
+	if v := d.GetRawConfig().GetAttr("require_availability"); !v.IsNull() && v.IsKnown() {
+		requireAvailability = v.True()
+	}
+	if !requireAvailability {
+		return nil
+	}
+	if !d.NewValueKnown("type") {
+		return nil
+	}
+	serverType := d.Get("type").(string)
+	var locations []string
+	if d.GetRawConfig().GetAttr("location").IsNull() {
+		if !d.NewValueKnown("location_preferences") {
+			return nil
+		}
+		for _, v := range d.Get("location_preferences").([]interface{}) {
+			locations = append(locations, fmt.Sprint(v))
+		}
+	} else {
+		if !d.NewValueKnown("location") {
+			return nil
+		}
+		locations = []string{d.Get("location").(string)}
+	}
+	locationList := strings.Join(locations, ", ")
+
+	quantity := meta.plannedServers.add(serverType, locationList)
+	availability, err := getServerAvailability(meta.client, serverType, locations, quantity)
+	if err != nil {
+		return fmt.Errorf("error checking availability of %s servers in %s: %v", serverType, locationList, err)
+	}
+	availableQuantity := 0
+	for _, location := range locations {
+		if availability[location].available {
+			return nil
+		}
+		availableQuantity = max(availableQuantity, availability[location].availableQuantity)
+	}
+	return fmt.Errorf("%d %s server(s) requested in %s, but only %d available", quantity, serverType, locationList, availableQuantity)
+}
@@ -0,0 +1,24 @@
+package pnap
+
+import "testing"
+
+func TestServerDemand_add(t *testing.T) {
+	demand := newServerDemand()
+	cases := []struct {
+		serverType, location string
+		expected             int
+	}{
+		{"s1.c1.small", "PHX", 1},
+		// Instances of count or for_each sharing a hostname are separate servers.
+		{"s1.c1.small", "PHX", 2},
+		{"s1.c1.small", "PHX", 3},
+		{"s1.c1.small", "ASH", 1},
+		{"s1.c1.small", "PHX, ASH", 1},
+		{"s2.c1.medium", "PHX", 1},
+	}
+	for _, c := range cases {
+		if actual := demand.add(c.serverType, c.location); actual != c.expected {
+			t.Errorf("add(%s, %s) = %d, expected %d", c.serverType, c.location, actual, c.expected)
+		}
+	}
+}
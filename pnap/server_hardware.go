@@ -0,0 +1,187 @@
+package pnap
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+// serverSizeRegexp matches a size in a hardware description, such as "480GB" or "1.92 TB".
+var serverSizeRegexp = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(TB|GB)\b`)
+
+// serverDiskRegexp matches a disk group of a storage description, such as "2x 480GB SSD". Units must end
+// a word, so that speeds such as "25Gbps" are not taken for sizes.
+var serverDiskRegexp = regexp.MustCompile(`(?i)^(?:(\d+)\s*x\s*)?(\d+(?:\.\d+)?)\s*(TB|GB)\b\s*(\S*)`)
+
+// serverDisk is a group of identical disks of a server.
+type serverDisk struct {
+	count    int
+	sizeInGb int
+	diskType string
+}
+
+// parseSizeInGb converts a size and its unit to GB.
+func parseSizeInGb(size string, unit string) int {
+	value, _ := strconv.ParseFloat(size, 64)
+	if strings.EqualFold(unit, "TB") {
+		value *= 1000
+	}
+	return int(math.Round(value))
+}
+
+// parseServerRamInGb returns the RAM in GB of a RAM description, such as "64GB", or 0 if it cannot be parsed.
+func parseServerRamInGb(ram string) int {
+	match := serverSizeRegexp.FindStringSubmatch(ram)
+	if match == nil {
+		return 0
+	}
+	return parseSizeInGb(match[1], match[2])
+}
+
+// parseServerDisks returns the disk groups of a storage description, such as "2x 960GB NVMe + 2x 4TB HDD".
+// Parts of the description that do not describe disks are skipped.
+func parseServerDisks(storage string) []serverDisk {
+	var disks []serverDisk
+	for _, part := range strings.FieldsFunc(storage, func(r rune) bool { return r == '+' || r == ',' }) {
+		match := serverDiskRegexp.FindStringSubmatch(strings.TrimSpace(part))
+		if match == nil {
+			continue
+		}
+		count := 1
+		if len(match[1]) > 0 {
+			count, _ = strconv.Atoi(match[1])
+		}
+		disks = append(disks, serverDisk{
+			count:    count,
+			sizeInGb: parseSizeInGb(match[2], match[3]),
+			diskType: match[4],
+		})
+	}
+	return disks
+}
+
+// parseServerDiskCount returns the number of disks in a storage description, or 0 if it cannot be parsed.
+func parseServerDiskCount(storage string) int {
+	count := 0
+	for _, disk := range parseServerDisks(storage) {
+		count += disk.count
+	}
+	return count
+}
+
+// serverHardwareSchema returns the schema of the structured hardware details of a server.
+func serverHardwareSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cpu": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"cpu_count": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"cores_per_cpu": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"total_cores": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"cpu_frequency_in_ghz": {
+					Type:     schema.TypeFloat,
+					Computed: true,
+				},
+				"ram_in_gb": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"disk_count": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"total_storage_in_gb": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"disks": {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"count": {
+								Type:     schema.TypeInt,
+								Computed: true,
+							},
+							"size_in_gb": {
+								Type:     schema.TypeInt,
+								Computed: true,
+							},
+							"type": {
+								Type:     schema.TypeString,
+								Computed: true,
+							},
+						},
+					},
+				},
+				"gpu_count": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"gpu_name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+// flattenServerHardware returns the structured hardware details of a server, parsed from its descriptions.
+func flattenServerHardware(instance bmcapi.Server) []interface{} {
+	hardware := make(map[string]interface{})
+	hardware["cpu"] = instance.Cpu
+	hardware["cpu_count"] = int(instance.CpuCount)
+	hardware["cores_per_cpu"] = int(instance.CoresPerCpu)
+	hardware["total_cores"] = int(instance.CpuCount * instance.CoresPerCpu)
+	hardware["cpu_frequency_in_ghz"] = customRound(float64(instance.CpuFrequency))
+	hardware["ram_in_gb"] = parseServerRamInGb(instance.Ram)
+
+	diskCount := 0
+	totalStorage := 0
+	var disks []interface{}
+	for _, disk := range parseServerDisks(instance.Storage) {
+		diskCount += disk.count
+		totalStorage += disk.count * disk.sizeInGb
+		disks = append(disks, map[string]interface{}{
+			"count":      disk.count,
+			"size_in_gb": disk.sizeInGb,
+			"type":       disk.diskType,
+		})
+	}
+	hardware["disk_count"] = diskCount
+	hardware["total_storage_in_gb"] = totalStorage
+	hardware["disks"] = disks
+
+	gpuCount := 0
+	gpuName := ""
+	if instance.GpuConfiguration != nil {
+		if instance.GpuConfiguration.Count != nil {
+			gpuCount = int(*instance.GpuConfiguration.Count)
+		}
+		if instance.GpuConfiguration.LongName != nil {
+			gpuName = *instance.GpuConfiguration.LongName
+		}
+	}
+	hardware["gpu_count"] = gpuCount
+	hardware["gpu_name"] = gpuName
+	return []interface{}{hardware}
+}
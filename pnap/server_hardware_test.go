@@ -0,0 +1,68 @@
+package pnap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseServerRamInGb(t *testing.T) {
+	cases := []struct {
+		ram      string
+		expected int
+	}{
+		{"64GB RAM", 64},
+		{"128GB RAM", 128},
+		{"1.5TB RAM", 1500},
+		{"768 GB RAM", 768},
+		{"64gb", 64},
+		{"", 0},
+		{"RAM", 0},
+	}
+	for _, c := range cases {
+		if actual := parseServerRamInGb(c.ram); actual != c.expected {
+			t.Errorf("parseServerRamInGb(%q) = %d, expected %d", c.ram, actual, c.expected)
+		}
+	}
+}
+
+func TestParseServerDisks(t *testing.T) {
+	cases := []struct {
+		storage  string
+		expected []serverDisk
+	}{
+		{"1x 960GB NVMe", []serverDisk{{1, 960, "NVMe"}}},
+		{"2x 2TB NVMe", []serverDisk{{2, 2000, "NVMe"}}},
+		{"2x 1.92TB NVMe", []serverDisk{{2, 1920, "NVMe"}}},
+		{"2x 3.84TB NVMe", []serverDisk{{2, 3840, "NVMe"}}},
+		{"2x 960GB NVMe + 2x 4TB HDD", []serverDisk{{2, 960, "NVMe"}, {2, 4000, "HDD"}}},
+		{"2x 480GB SSD, 6x 7.68TB NVMe", []serverDisk{{2, 480, "SSD"}, {6, 7680, "NVMe"}}},
+		{"1x 480GB SSD + 2x 1.92TB NVMe + 4x 16TB HDD", []serverDisk{{1, 480, "SSD"}, {2, 1920, "NVMe"}, {4, 16000, "HDD"}}},
+		{"480GB SSD", []serverDisk{{1, 480, "SSD"}}},
+		{"2 x 1TB", []serverDisk{{2, 1000, ""}}},
+		{"2x 25Gbps", nil},
+		{"", nil},
+		{"N/A", nil},
+	}
+	for _, c := range cases {
+		if actual := parseServerDisks(c.storage); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parseServerDisks(%q) = %v, expected %v", c.storage, actual, c.expected)
+		}
+	}
+}
+
+func TestParseServerDiskCount(t *testing.T) {
+	cases := []struct {
+		storage  string
+		expected int
+	}{
+		{"1x 960GB NVMe", 1},
+		{"2x 960GB NVMe + 2x 4TB HDD", 4},
+		{"1x 480GB SSD + 2x 1.92TB NVMe + 4x 16TB HDD", 7},
+		{"", 0},
+	}
+	for _, c := range cases {
+		if actual := parseServerDiskCount(c.storage); actual != c.expected {
+			t.Errorf("parseServerDiskCount(%q) = %d, expected %d", c.storage, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
+	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	ipAllocationManual = "manual"
+	ipAllocationAuto   = "auto"
+)
+
+// ipAllocations tracks the public network addresses allocated to servers by a provider instance, so that servers
+// created in parallel are not given the same address before their network memberships are visible in the API.
+type ipAllocations struct {
+	lock     sync.Mutex
+	reserved map[string]map[string]bool
+}
+
+// newIpAllocations returns an empty ipAllocations.
+func newIpAllocations() *ipAllocations {
+	return &ipAllocations{
+		reserved: make(map[string]map[string]bool),
+	}
+}
+
+// ipAllocation is an address allocated to a server in a public network.
+type ipAllocation struct {
+	networkID string
+	ip        string
+}
+
+// allocate picks a free IPv4 address of the public network, from the usable addresses of its IP Blocks that are
+// neither used by network members nor allocated before, and reserves it.
+func (a *ipAllocations) allocate(client receiver.BMCSDK, networkID string) (string, error) {
+	requestCommand := publicnetwork.NewGetPublicNetworkCommand(client, networkID)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return "", err
+	}
+	used := make(map[string]bool)
+	for _, j := range resp.Memberships {
+		for _, ip := range divideIpsRange(j.Ips) {
+			used[ip] = true
+		}
+	}
+	var cidrs []string
+	for _, j := range resp.IpBlocks {
+		cidrs = append(cidrs, j.Cidr)
+	}
+	return a.reserve(networkID, cidrs, used)
+}
+
+// reserve reserves the first usable address of the IP Blocks with the CIDRs that is neither used nor reserved.
+func (a *ipAllocations) reserve(networkID string, cidrs []string, used map[string]bool) (string, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.reserved[networkID] == nil {
+		a.reserved[networkID] = make(map[string]bool)
+	}
+	for _, cidr := range cidrs {
+		addresses, err := computeIpBlockAddresses(cidr)
+		if err != nil {
+			continue
+		}
+		for _, ip := range addresses.usable {
+			if !used[ip] && !a.reserved[networkID][ip] {
+				a.reserved[networkID][ip] = true
+				return ip, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no free IPv4 address in the IP blocks of public network %s", networkID)
+}
+
+// release releases addresses allocated to a server that was not created, so that other servers can use them.
+func (a *ipAllocations) release(allocations []ipAllocation) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, allocation := range allocations {
+		delete(a.reserved[allocation.networkID], allocation.ip)
+	}
+}
+
+// allocateServerPublicNetworkIps sets the ips of server public networks with automatic IP allocation
+// and no ips yet to a free address of the network, and returns the allocated addresses. On error, the addresses
+// allocated so far are released.
+func allocateServerPublicNetworkIps(d *schema.ResourceData, meta *providerMeta) ([]ipAllocation, error) {
+	networkConfiguration := d.Get("network_configuration").([]interface{})
+	if len(networkConfiguration) == 0 || networkConfiguration[0] == nil {
+		return nil, nil
+	}
+	publicNetworkConfiguration, _ := networkConfiguration[0].(map[string]interface{})["public_network_configuration"].([]interface{})
+	if len(publicNetworkConfiguration) == 0 || publicNetworkConfiguration[0] == nil {
+		return nil, nil
+	}
+	publicNetworks, _ := publicNetworkConfiguration[0].(map[string]interface{})["public_networks"].([]interface{})
+
+	var allocated []ipAllocation
+	for _, j := range publicNetworks {
+		serverPublicNetwork := j.(map[string]interface{})["server_public_network"].([]interface{})
+		if len(serverPublicNetwork) == 0 || serverPublicNetwork[0] == nil {
+			continue
+		}
+		serverPublicNetworkItem := serverPublicNetwork[0].(map[string]interface{})
+		ips := serverPublicNetworkItem["ips"].(*schema.Set)
+		id := serverPublicNetworkItem["id"].(string)
+		if serverPublicNetworkItem["ip_allocation"].(string) != ipAllocationAuto || ips.Len() > 0 {
+			continue
+		}
+		ip, err := meta.allocatedIps.allocate(meta.client, id)
+		if err != nil {
+			meta.allocatedIps.release(allocated)
+			return nil, err
+		}
+		ips.Add(ip)
+		allocated = append(allocated, ipAllocation{networkID: id, ip: ip})
+	}
+	if len(allocated) == 0 {
+		return nil, nil
+	}
+	if err := d.Set("network_configuration", networkConfiguration); err != nil {
+		meta.allocatedIps.release(allocated)
+		return nil, err
+	}
+	return allocated, nil
+}
+
+// suppressIpAllocationDiff ignores changes to ip_allocation of existing servers, since addresses are only
+// allocated when a server is created.
+func suppressIpAllocationDiff(k, old, new string, d *schema.ResourceData) bool {
+	return len(d.Id()) > 0
+}
+
+// customizeDiffServerIpAllocation checks during plan that each public network of a new server has ips, uses
+// automatic IP allocation or computes a SLAAC IP. Networks with values that are not known yet are not checked.
+func customizeDiffServerIpAllocation(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	networkConfiguration := d.GetRawConfig().GetAttr("network_configuration")
+	if len(d.Id()) > 0 || networkConfiguration.IsNull() || !networkConfiguration.IsKnown() {
+		return nil
+	}
+	for _, nc := range networkConfiguration.AsValueSlice() {
+		publicNetworkConfiguration := nc.GetAttr("public_network_configuration")
+		if publicNetworkConfiguration.IsNull() || !publicNetworkConfiguration.IsKnown() {
+			continue
+		}
+		for _, pnc := range publicNetworkConfiguration.AsValueSlice() {
+			publicNetworks := pnc.GetAttr("public_networks")
+			if publicNetworks.IsNull() || !publicNetworks.IsKnown() {
+				continue
+			}
+			for _, pn := range publicNetworks.AsValueSlice() {
+				serverPublicNetwork := pn.GetAttr("server_public_network")
+				if serverPublicNetwork.IsNull() || !serverPublicNetwork.IsKnown() {
+					continue
+				}
+				for _, spn := range serverPublicNetwork.AsValueSlice() {
+					ips, ipAllocation, computeSlaacIp := spn.GetAttr("ips"), spn.GetAttr("ip_allocation"), spn.GetAttr("compute_slaac_ip")
+					if !ips.IsKnown() || !ipAllocation.IsKnown() || !computeSlaacIp.IsKnown() {
+						continue
+					}
+					if (!ips.IsNull() && ips.LengthInt() > 0) ||
+						(!ipAllocation.IsNull() && ipAllocation.AsString() == ipAllocationAuto) ||
+						(!computeSlaacIp.IsNull() && computeSlaacIp.True()) {
+						continue
+					}
+					id := "<unknown>"
+					if v := spn.GetAttr("id"); v.IsKnown() && !v.IsNull() {
+						id = v.AsString()
+					}
+					return fmt.Errorf("ips of public network %s must be set unless ip_allocation is %q or compute_slaac_ip is true", id, ipAllocationAuto)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package pnap
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIpAllocations_reserve(t *testing.T) {
+	allocations := newIpAllocations()
+	cidrs := []string{"2001:db8::/64", "203.0.113.0/30", "198.51.100.0/31"}
+	used := map[string]bool{"203.0.113.2": true}
+
+	// IPv6 blocks have no usable addresses to allocate, the only usable address of the /30 is used,
+	// and the first address of the /31 is its gateway.
+	if actual, err := allocations.reserve("network-1", cidrs, used); err != nil || actual != "198.51.100.1" {
+		t.Errorf("reserve() = %s, %v, expected 198.51.100.1", actual, err)
+	}
+	if actual, err := allocations.reserve("network-1", cidrs, used); err == nil {
+		t.Errorf("reserve() of an exhausted network = %s, expected an error", actual)
+	}
+	// Reservations are kept per network.
+	if actual, err := allocations.reserve("network-2", cidrs, nil); err != nil || actual != "203.0.113.2" {
+		t.Errorf("reserve() in another network = %s, %v, expected 203.0.113.2", actual, err)
+	}
+	if actual, err := allocations.reserve("network-3", nil, nil); err == nil {
+		t.Errorf("reserve() in a network without IP blocks = %s, expected an error", actual)
+	}
+}
+
+func TestIpAllocations_release(t *testing.T) {
+	allocations := newIpAllocations()
+	cidrs := []string{"203.0.113.0/29"}
+	first, _ := allocations.reserve("network-1", cidrs, nil)
+	second, _ := allocations.reserve("network-1", cidrs, nil)
+
+	// The addresses of a server that failed to be created are given to the next server.
+	allocations.release([]ipAllocation{{networkID: "network-1", ip: first}})
+	if actual, err := allocations.reserve("network-1", cidrs, nil); err != nil || actual != first {
+		t.Errorf("reserve() after release = %s, %v, expected %s", actual, err, first)
+	}
+	if actual, _ := allocations.reserve("network-1", cidrs, nil); actual == first || actual == second {
+		t.Errorf("reserve() = %s, which is already reserved", actual)
+	}
+	// Releasing addresses that were never reserved is a no-op.
+	allocations.release([]ipAllocation{{networkID: "network-2", ip: first}})
+	allocations.release(nil)
+}
+
+func TestIpAllocations_reserveConcurrently(t *testing.T) {
+	allocations := newIpAllocations()
+	cidrs := []string{"203.0.113.0/28"}
+	results := make(chan string, 13)
+	var wg sync.WaitGroup
+	for i := 0; i < 13; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ip, err := allocations.reserve("network-1", cidrs, nil)
+			if err != nil {
+				t.Errorf("reserve() returned error: %v", err)
+			}
+			results <- ip
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[string]bool)
+	for ip := range results {
+		if seen[ip] {
+			t.Errorf("%s was reserved twice", ip)
+		}
+		seen[ip] = true
+	}
+}
@@ -0,0 +1,225 @@
+package pnap
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
+)
+
+const (
+	serverNetworkTypePrivate = "private"
+	serverNetworkTypePublic  = "public"
+
+	serverNetworkStatusAssigned   = "assigned"
+	serverNetworkStatusUnassigned = "unassigned"
+)
+
+// serverNetworkMembership holds the membership of a server in a private or public network.
+type serverNetworkMembership struct {
+	ips               []string
+	dhcp              *bool
+	statusDescription string
+	vlanID            *int32
+}
+
+// getServerNetworkMembership returns the membership of the server in the network, or nil if the server
+// is not a member of the network.
+func getServerNetworkMembership(client receiver.BMCSDK, serverID string, networkType string, networkID string) (*serverNetworkMembership, error) {
+	requestCommand := server.NewGetServerCommand(client, serverID)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return nil, err
+	}
+	return findServerNetworkMembership(resp.NetworkConfiguration, networkType, networkID), nil
+}
+
+// findServerNetworkMembership looks up the membership in the network configuration of a server.
+func findServerNetworkMembership(netConf bmcapiclient.NetworkConfiguration, networkType string, networkID string) *serverNetworkMembership {
+	switch networkType {
+	case serverNetworkTypePrivate:
+		if netConf.PrivateNetworkConfiguration == nil {
+			return nil
+		}
+		for _, j := range netConf.PrivateNetworkConfiguration.PrivateNetworks {
+			if j.Id == networkID {
+				membership := &serverNetworkMembership{ips: j.Ips, dhcp: j.Dhcp, vlanID: j.VlanId}
+				if j.StatusDescription != nil {
+					membership.statusDescription = *j.StatusDescription
+				}
+				return membership
+			}
+		}
+	case serverNetworkTypePublic:
+		if netConf.PublicNetworkConfiguration == nil {
+			return nil
+		}
+		for _, j := range netConf.PublicNetworkConfiguration.PublicNetworks {
+			if j.Id == networkID {
+				membership := &serverNetworkMembership{ips: j.Ips, vlanID: j.VlanId}
+				if j.StatusDescription != nil {
+					membership.statusDescription = *j.StatusDescription
+				}
+				return membership
+			}
+		}
+	}
+	return nil
+}
+
+// flattenServerNetworkIps keeps the configured IPs if they cover the same addresses as the IPs read from the API,
+// which may list ranges differently.
+func flattenServerNetworkIps(ipsApi []string, ipsInput *schema.Set) []interface{} {
+	input := ipsInput.List()
+	ipsInputS := make([]string, len(input))
+	for i, j := range input {
+		ipsInputS[i] = j.(string)
+	}
+	if len(ipsInputS) > 0 && compareIps(divideIpsRange(ipsApi), removeDuplicateIps(divideIpsRange(ipsInputS))) {
+		return input
+	}
+	ips := make([]interface{}, len(ipsApi))
+	for i, j := range ipsApi {
+		ips[i] = j
+	}
+	return ips
+}
+
+// mergeUntrackedMemberships adds the private and public network memberships of the server that are missing from the
+// network configuration, such as the ones managed with pnap_server_private_network and pnap_server_public_network,
+// so that installing the OS again keeps them.
+func mergeUntrackedMemberships(netConf *bmcapiclient.NetworkConfiguration, current bmcapiclient.NetworkConfiguration) *bmcapiclient.NetworkConfiguration {
+	merged := bmcapiclient.NetworkConfiguration{}
+	if netConf != nil {
+		merged = *netConf
+	}
+	added := false
+	if current.PrivateNetworkConfiguration != nil {
+		for _, j := range current.PrivateNetworkConfiguration.PrivateNetworks {
+			if findServerNetworkMembership(merged, serverNetworkTypePrivate, j.Id) != nil {
+				continue
+			}
+			if merged.PrivateNetworkConfiguration == nil {
+				merged.PrivateNetworkConfiguration = &bmcapiclient.PrivateNetworkConfiguration{ConfigurationType: current.PrivateNetworkConfiguration.ConfigurationType}
+			}
+			merged.PrivateNetworkConfiguration.PrivateNetworks = append(merged.PrivateNetworkConfiguration.PrivateNetworks,
+				bmcapiclient.ServerPrivateNetwork{Id: j.Id, Ips: j.Ips, Dhcp: j.Dhcp})
+			added = true
+		}
+	}
+	if current.PublicNetworkConfiguration != nil {
+		for _, j := range current.PublicNetworkConfiguration.PublicNetworks {
+			if findServerNetworkMembership(merged, serverNetworkTypePublic, j.Id) != nil {
+				continue
+			}
+			if merged.PublicNetworkConfiguration == nil {
+				merged.PublicNetworkConfiguration = &bmcapiclient.PublicNetworkConfiguration{}
+			}
+			merged.PublicNetworkConfiguration.PublicNetworks = append(merged.PublicNetworkConfiguration.PublicNetworks,
+				bmcapiclient.ServerPublicNetwork{Id: j.Id, Ips: j.Ips})
+			added = true
+		}
+	}
+	if netConf == nil && !added {
+		return nil
+	}
+	return &merged
+}
+
+// serverMemberIds returns the IDs of the servers among the network members, once each.
+func serverMemberIds(memberships []networkapiclient.NetworkMembership) []string {
+	var serverIDs []string
+	seen := make(map[string]bool)
+	for _, j := range memberships {
+		if j.ResourceType != "server" || seen[j.ResourceId] {
+			continue
+		}
+		seen[j.ResourceId] = true
+		serverIDs = append(serverIDs, j.ResourceId)
+	}
+	return serverIDs
+}
+
+// detachNetworkServers removes the servers among the network members from the network and returns their IDs.
+// Other members, such as storage networks, cannot be detached this way and are left in place.
+func detachNetworkServers(meta *providerMeta, networkType string, networkID string, memberships []networkapiclient.NetworkMembership) ([]string, error) {
+	client := meta.client
+	var detached []string
+	for _, serverID := range serverMemberIds(memberships) {
+		meta.locks.Lock(serverID)
+		var err error
+		switch networkType {
+		case serverNetworkTypePrivate:
+			requestCommand := server.NewRemoveServerPrivateNetworkCommand(client, serverID, networkID)
+			_, err = requestCommand.Execute()
+		case serverNetworkTypePublic:
+			requestCommand := server.NewRemoveServerPublicNetworkCommand(client, serverID, networkID)
+			_, err = requestCommand.Execute()
+		}
+		meta.locks.Unlock(serverID)
+		if err != nil {
+			return detached, fmt.Errorf("error detaching server (%s) from %s network (%s), detached servers before: [%s]: %v", serverID, networkType, networkID, strings.Join(detached, ", "), err)
+		}
+		detached = append(detached, serverID)
+	}
+	log.Printf("[INFO] Detached %d server(s) from %s network %s: [%s]", len(detached), networkType, networkID, strings.Join(detached, ", "))
+	return detached, nil
+}
+
+// detachedServersWarning warns that the servers detached from the network still list it in their pnap_server state.
+func detachedServersWarning(networkType string, networkID string, detached []string) diag.Diagnostics {
+	if len(detached) == 0 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Detached %d server(s) from %s network %s", len(detached), networkType, networkID),
+		Detail: fmt.Sprintf("The servers [%s] were detached from the %s network before it was destroyed. The network_configuration "+
+			"of the pnap_server resources managing them still lists the network, so their state drifts until the network "+
+			"is removed from their configuration.", strings.Join(detached, ", "), networkType),
+	}}
+}
+
+// serverNetworkWaitForStatus waits for the membership of the server in the network to reach the target status.
+func serverNetworkWaitForStatus(client *receiver.BMCSDK, serverID string, networkType string, networkID string, target string, timeout time.Duration) error {
+	log.Printf("Waiting for server %s to be %s to %s network %s...", serverID, target, networkType, networkID)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"assigning", "unassigning", "in-progress", serverNetworkStatusAssigned, serverNetworkStatusUnassigned},
+		Target:     []string{target},
+		Refresh:    refreshForServerNetworkStatus(client, serverID, networkType, networkID),
+		Timeout:    timeout,
+		Delay:      pnapPublicNetworkRetryDelay,
+		MinTimeout: pnapRetryMinTimeout,
+	}
+
+	_, err := stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf("error waiting for server (%s) to be %s to %s network (%s): %v", serverID, target, networkType, networkID, err)
+	}
+
+	return nil
+}
+
+func refreshForServerNetworkStatus(client *receiver.BMCSDK, serverID string, networkType string, networkID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		membership, err := getServerNetworkMembership(*client, serverID, networkType, networkID)
+		if err != nil {
+			return 0, "", err
+		} else if membership == nil {
+			return 0, serverNetworkStatusUnassigned, nil
+		} else if membership.statusDescription == "error" {
+			return 0, "", fmt.Errorf("server (%s) membership in %s network (%s) failed", serverID, networkType, networkID)
+		}
+		return 0, membership.statusDescription, nil
+	}
+}
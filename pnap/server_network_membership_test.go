@@ -0,0 +1,110 @@
+package pnap
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
+)
+
+func TestFindServerNetworkMembership(t *testing.T) {
+	dhcp := false
+	assigned := serverNetworkStatusAssigned
+	vlanID := int32(10)
+	netConf := bmcapiclient.NetworkConfiguration{
+		PrivateNetworkConfiguration: &bmcapiclient.PrivateNetworkConfiguration{
+			PrivateNetworks: []bmcapiclient.ServerPrivateNetwork{
+				{Id: "private-1", Ips: []string{"10.0.0.11"}, Dhcp: &dhcp, StatusDescription: &assigned, VlanId: &vlanID},
+			},
+		},
+		PublicNetworkConfiguration: &bmcapiclient.PublicNetworkConfiguration{
+			PublicNetworks: []bmcapiclient.ServerPublicNetwork{
+				{Id: "public-1", Ips: []string{"182.16.0.146"}},
+			},
+		},
+	}
+
+	membership := findServerNetworkMembership(netConf, serverNetworkTypePrivate, "private-1")
+	if membership == nil {
+		t.Fatal("expected a membership in private-1")
+	}
+	if !reflect.DeepEqual(membership.ips, []string{"10.0.0.11"}) || membership.dhcp == nil || *membership.dhcp ||
+		membership.statusDescription != serverNetworkStatusAssigned || membership.vlanID == nil || *membership.vlanID != 10 {
+		t.Errorf("unexpected membership in private-1: %+v", membership)
+	}
+
+	membership = findServerNetworkMembership(netConf, serverNetworkTypePublic, "public-1")
+	if membership == nil {
+		t.Fatal("expected a membership in public-1")
+	}
+	if !reflect.DeepEqual(membership.ips, []string{"182.16.0.146"}) || membership.statusDescription != "" || membership.vlanID != nil {
+		t.Errorf("unexpected membership in public-1: %+v", membership)
+	}
+
+	// A network ID is only looked up among networks of the requested type.
+	if membership := findServerNetworkMembership(netConf, serverNetworkTypePublic, "private-1"); membership != nil {
+		t.Errorf("expected no public membership in private-1, got %+v", membership)
+	}
+	if membership := findServerNetworkMembership(netConf, serverNetworkTypePrivate, "private-2"); membership != nil {
+		t.Errorf("expected no membership in private-2, got %+v", membership)
+	}
+	if membership := findServerNetworkMembership(bmcapiclient.NetworkConfiguration{}, serverNetworkTypePrivate, "private-1"); membership != nil {
+		t.Errorf("expected no membership without private network configuration, got %+v", membership)
+	}
+	if membership := findServerNetworkMembership(bmcapiclient.NetworkConfiguration{}, serverNetworkTypePublic, "public-1"); membership != nil {
+		t.Errorf("expected no membership without public network configuration, got %+v", membership)
+	}
+}
+
+func TestFlattenServerNetworkIps(t *testing.T) {
+	ipsSet := func(ips ...interface{}) *schema.Set {
+		return schema.NewSet(schema.HashString, ips)
+	}
+	sorted := func(ips []interface{}) []string {
+		result := make([]string, len(ips))
+		for i, ip := range ips {
+			result[i] = ip.(string)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	cases := []struct {
+		name     string
+		ipsApi   []string
+		ipsInput *schema.Set
+		expected []string
+	}{
+		{"no configured ips", []string{"10.0.0.11"}, ipsSet(), []string{"10.0.0.11"}},
+		{"same ips", []string{"10.0.0.11", "10.0.0.12"}, ipsSet("10.0.0.12", "10.0.0.11"), []string{"10.0.0.11", "10.0.0.12"}},
+		{"range read as single ips", []string{"10.0.0.11", "10.0.0.12", "10.0.0.13"}, ipsSet("10.0.0.11 - 10.0.0.13"), []string{"10.0.0.11 - 10.0.0.13"}},
+		{"changed outside", []string{"10.0.0.20"}, ipsSet("10.0.0.11"), []string{"10.0.0.20"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := sorted(flattenServerNetworkIps(c.ipsApi, c.ipsInput)); !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestServerMemberIds(t *testing.T) {
+	memberships := []networkapiclient.NetworkMembership{
+		{ResourceId: "server-1", ResourceType: "server", Ips: []string{"10.0.0.11"}},
+		{ResourceId: "storage-1", ResourceType: "storage", Ips: []string{"10.0.0.100"}},
+		{ResourceId: "server-2", ResourceType: "server", Ips: []string{"10.0.0.12"}},
+		{ResourceId: "server-1", ResourceType: "server", Ips: []string{"10.0.0.13"}},
+	}
+	expected := []string{"server-1", "server-2"}
+	if actual := serverMemberIds(memberships); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if actual := serverMemberIds(nil); len(actual) != 0 {
+		t.Errorf("expected no servers, got %v", actual)
+	}
+}
@@ -0,0 +1,62 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/product"
+	"github.com/PNAP/go-sdk-helper-bmc/dto"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	serverRaidNone = "NO_RAID"
+	serverRaid0    = "RAID_0"
+	serverRaid1    = "RAID_1"
+)
+
+// serverRaidMinDisks holds the number of disks each software RAID option requires.
+var serverRaidMinDisks = map[string]int{
+	serverRaidNone: 1,
+	serverRaid0:    2,
+	serverRaid1:    2,
+}
+
+// suppressRootPartitionSizeDiff suppresses the difference between a root partition size of -1, which uses all
+// available space, and the size read from the API for an existing server, such as when storage_configuration is
+// added to its configuration. Otherwise, the plan would install the OS again.
+func suppressRootPartitionSizeDiff(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	return len(d.Id()) > 0 && newValue == "-1" && len(oldValue) > 0
+}
+
+// customizeDiffServerStorage checks during plan that the RAID option of the root partition can be set up
+// with the disks of the server type, as described in the products catalog.
+func customizeDiffServerStorage(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("storage_configuration") || !d.NewValueKnown("type") || !d.GetRawConfig().GetAttr("storage_configuration").IsWhollyKnown() {
+		return nil
+	}
+	raid, _ := d.Get("storage_configuration.0.root_partition.0.raid").(string)
+	if serverRaidMinDisks[raid] < 2 {
+		return nil
+	}
+	serverType := d.Get("type").(string)
+
+	query := dto.ProductQuery{}
+	query.ProductCode = serverType
+	query.ProductCategory = "SERVER"
+	requestCommand := product.NewGetProductsCommand(m.(*providerMeta).client, query)
+	resp, err := requestCommand.Execute()
+	if err != nil {
+		return fmt.Errorf("error reading product %s: %v", serverType, err)
+	}
+	for _, j := range resp {
+		if j.ProductCode != serverType {
+			continue
+		}
+		disks := parseServerDiskCount(j.Metadata.Storage)
+		if disks > 0 && disks < serverRaidMinDisks[raid] {
+			return fmt.Errorf("%s requires at least %d disks, but server type %s has %d (%s)", raid, serverRaidMinDisks[raid], serverType, disks, j.Metadata.Storage)
+		}
+	}
+	return nil
+}
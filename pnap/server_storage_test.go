@@ -0,0 +1,67 @@
+package pnap
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+func TestFlattenServerStorageConfiguration(t *testing.T) {
+	raid := serverRaid1
+	size := int32(250)
+	read := bmcapiclient.StorageConfiguration{RootPartition: &bmcapiclient.StorageConfigurationRootPartition{Raid: &raid, Size: &size}}
+	configured := func(size int) []interface{} {
+		return []interface{}{map[string]interface{}{
+			"root_partition": []interface{}{map[string]interface{}{"raid": serverRaid1, "size": size}},
+		}}
+	}
+
+	cases := []struct {
+		name     string
+		current  []interface{}
+		expected int
+	}{
+		{"use remaining space", configured(-1), -1},
+		{"fixed size", configured(250), 250},
+		{"changed outside", configured(100), 250},
+		{"not configured", nil, 250},
+	}
+	for _, c := range cases {
+		sc := flattenServerStorageConfiguration(read, c.current)
+		rootPartition := sc[0].(map[string]interface{})["root_partition"].([]interface{})[0].(map[string]interface{})
+		if rootPartition["size"] != c.expected || rootPartition["raid"] != serverRaid1 {
+			t.Errorf("%s: flattenServerStorageConfiguration() = %v, expected size %d and raid %s", c.name, rootPartition, c.expected, serverRaid1)
+		}
+	}
+
+	if sc := flattenServerStorageConfiguration(bmcapiclient.StorageConfiguration{}, configured(-1)); sc != nil {
+		t.Errorf("flattenServerStorageConfiguration() without a root partition = %v, expected nil", sc)
+	}
+}
+
+func TestSuppressRootPartitionSizeDiff(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceServer().Schema, map[string]interface{}{})
+	key := "storage_configuration.0.root_partition.0.size"
+	if suppressRootPartitionSizeDiff(key, "", "-1", d) {
+		t.Errorf("expected the size of a new server not to be suppressed")
+	}
+
+	d.SetId("5ff5cc9bc1acf144d9106233")
+	cases := []struct {
+		oldValue, newValue string
+		expected           bool
+	}{
+		{"250", "-1", true},
+		{"-1", "-1", true},
+		{"250", "100", false},
+		{"-1", "100", false},
+		{"", "100", false},
+	}
+	for _, c := range cases {
+		if actual := suppressRootPartitionSizeDiff(key, c.oldValue, c.newValue, d); actual != c.expected {
+			t.Errorf("suppressRootPartitionSizeDiff(%q, %q) = %t, expected %t", c.oldValue, c.newValue, actual, c.expected)
+		}
+	}
+}
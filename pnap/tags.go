@@ -0,0 +1,146 @@
+package pnap
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// tagAssignment is a tag name and value pair as set in configuration.
+type tagAssignment struct {
+	name  string
+	value string
+}
+
+// expandTagAssignments reads tag assignments from a list of tags blocks holding a tag_assignment block.
+func expandTagAssignments(tags []interface{}) []tagAssignment {
+	tagAssignments := make([]tagAssignment, 0, len(tags))
+	for _, j := range tags {
+		tagsItem, ok := j.(map[string]interface{})
+		if !ok || tagsItem["tag_assignment"] == nil || len(tagsItem["tag_assignment"].([]interface{})) == 0 {
+			continue
+		}
+		tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
+		tagAssignItem, ok := tagAssign.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tagAssignmentObject := tagAssignment{}
+		tagAssignmentObject.name = tagAssignItem["name"].(string)
+		if tagAssignItem["value"] != nil {
+			tagAssignmentObject.value = tagAssignItem["value"].(string)
+		}
+		tagAssignments = append(tagAssignments, tagAssignmentObject)
+	}
+	return tagAssignments
+}
+
+// mergeDefaultTags returns the provider default tags followed by the resource tags.
+// A resource tag replaces the default tag of the same name.
+func mergeDefaultTags(defaultTags []tagAssignment, tags []tagAssignment) []tagAssignment {
+	merged := make([]tagAssignment, 0, len(defaultTags)+len(tags))
+	for _, defaultTag := range defaultTags {
+		if !containsTag(tags, defaultTag.name) {
+			merged = append(merged, defaultTag)
+		}
+	}
+	return append(merged, tags...)
+}
+
+// containsTag checks whether a tag with the given name is among tag assignments.
+func containsTag(tags []tagAssignment, name string) bool {
+	for _, tag := range tags {
+		if tag.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isManagedTag checks whether a tag read from the API is managed by the resource, either through its tags,
+// the provider default tags or as one of the tags it set previously according to tags_all.
+// Tags assigned by other means, such as pnap_tag_assignment, are left alone.
+func isManagedTag(name string, tags []tagAssignment, defaultTags []tagAssignment, tagsAll map[string]interface{}) bool {
+	if _, exists := tagsAll[name]; exists {
+		return true
+	}
+	return containsTag(tags, name) || containsTag(defaultTags, name)
+}
+
+// readsAllTags checks whether every tag read from the API is taken as managed by the resource. This is the case
+// on the first read of a resource, such as after import, when neither tags nor a previous tags_all tell which
+// tags the resource manages.
+func readsAllTags(d *schema.ResourceData) bool {
+	if len(d.Get("tags").([]interface{})) > 0 || len(d.Get("tags_all").(map[string]interface{})) > 0 {
+		return false
+	}
+	state := d.GetRawState()
+	return state.IsNull() || state.GetAttr("location").IsNull()
+}
+
+// isResourceTag checks whether a managed tag read from the API is shown in the resource tags. When all tags are
+// read, every tag except the provider default tags is shown.
+func isResourceTag(name string, tags []tagAssignment, defaultTags []tagAssignment, readAll bool) bool {
+	if containsTag(tags, name) {
+		return true
+	}
+	return readAll && !containsTag(defaultTags, name)
+}
+
+// setTag returns tag assignments with the value of the named tag set, adding the tag if it is not assigned yet.
+func setTag(tags []tagAssignment, tag tagAssignment) []tagAssignment {
+	result := make([]tagAssignment, 0, len(tags)+1)
+	for _, j := range tags {
+		if j.name != tag.name {
+			result = append(result, j)
+		}
+	}
+	return append(result, tag)
+}
+
+// removeTag returns tag assignments without the named tag.
+func removeTag(tags []tagAssignment, name string) []tagAssignment {
+	result := make([]tagAssignment, 0, len(tags))
+	for _, j := range tags {
+		if j.name != name {
+			result = append(result, j)
+		}
+	}
+	return result
+}
+
+// mergeUnmanagedTags returns the resource tags together with the currently assigned tags
+// that the resource did not manage so far according to the previous tags_all.
+func mergeUnmanagedTags(current []tagAssignment, previousTagsAll map[string]interface{}, tags []tagAssignment) []tagAssignment {
+	merged := make([]tagAssignment, 0, len(current)+len(tags))
+	for _, j := range current {
+		if _, managed := previousTagsAll[j.name]; !managed && !containsTag(tags, j.name) {
+			merged = append(merged, j)
+		}
+	}
+	return append(merged, tags...)
+}
+
+// tagAssignmentsToMap converts tag assignments to the tags_all map format.
+func tagAssignmentsToMap(tags []tagAssignment) map[string]interface{} {
+	tagsMap := make(map[string]interface{}, len(tags))
+	for _, tag := range tags {
+		tagsMap[tag.name] = tag.value
+	}
+	return tagsMap
+}
+
+// customizeDiffTagsAll plans tags_all as the resource tags merged with the provider default tags,
+// so that changes to either of them are applied to the resource.
+func customizeDiffTagsAll(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("tags") {
+		return d.SetNewComputed("tags_all")
+	}
+	tags := expandTagAssignments(d.Get("tags").([]interface{}))
+	tagsAll := tagAssignmentsToMap(mergeDefaultTags(m.(*providerMeta).defaultTags, tags))
+	if !reflect.DeepEqual(d.Get("tags_all").(map[string]interface{}), tagsAll) {
+		return d.SetNew("tags_all", tagsAll)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package pnap
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestReadsAllTags_import(t *testing.T) {
+	// An imported resource holds nothing but its ID before the first read.
+	d := resourceIpBlock().Data(nil)
+	d.SetId("6047127fed34ecc3ba8402d2")
+	if !readsAllTags(d) {
+		t.Errorf("readsAllTags() = false for an imported IP block, expected true")
+	}
+
+	defaultTags := []tagAssignment{{name: "env", value: "dev"}}
+	for _, name := range []string{"owner", "team"} {
+		if !isResourceTag(name, nil, defaultTags, true) {
+			t.Errorf("isResourceTag(%q) = false on import, expected true", name)
+		}
+	}
+	if isResourceTag("env", nil, defaultTags, true) {
+		t.Errorf("isResourceTag(%q) = true for a provider default tag on import, expected false", "env")
+	}
+}
+
+func TestReadsAllTags_managedTags(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceIpBlock().Schema, map[string]interface{}{
+		"location":        "PHX",
+		"cidr_block_size": "/31",
+		"tags": []interface{}{
+			map[string]interface{}{
+				"tag_assignment": []interface{}{
+					map[string]interface{}{"name": "owner", "value": "ops"},
+				},
+			},
+		},
+	})
+	if readsAllTags(d) {
+		t.Errorf("readsAllTags() = true with configured tags, expected false")
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceIpBlock().Schema, map[string]interface{}{
+		"location":        "PHX",
+		"cidr_block_size": "/31",
+	})
+	d.Set("tags_all", map[string]interface{}{"owner": "ops"})
+	if readsAllTags(d) {
+		t.Errorf("readsAllTags() = true with a previous tags_all, expected false")
+	}
+
+	tags := []tagAssignment{{name: "owner", value: "ops"}}
+	if !isResourceTag("owner", tags, nil, false) {
+		t.Errorf("isResourceTag(%q) = false for a configured tag, expected true", "owner")
+	}
+	if isResourceTag("team", tags, nil, false) {
+		t.Errorf("isResourceTag(%q) = true for a tag that is not configured, expected false", "team")
+	}
+}